@@ -0,0 +1,30 @@
+package main
+
+import (
+	// Local Packages
+	apiclient "scheduler/utils/apiclient"
+
+	// External Packages
+	"github.com/alecthomas/kingpin/v2"
+)
+
+// apiFlags are the connection flags shared by the subcommands that act as API clients.
+type apiFlags struct {
+	server    *string
+	apiKey    *string
+	token     *string
+	namespace *string
+}
+
+func newAPIFlags(cmd *kingpin.CmdClause) *apiFlags {
+	return &apiFlags{
+		server:    cmd.Flag("server", "Scheduler API Base URL").Default("http://localhost:4202/scheduler/v1").Envar("SCHEDULER_SERVER").String(),
+		apiKey:    cmd.Flag("api-key", "API Key Used To Authenticate").Envar("SCHEDULER_API_KEY").String(),
+		token:     cmd.Flag("token", "Bearer Token Used To Authenticate").Envar("SCHEDULER_TOKEN").String(),
+		namespace: cmd.Flag("namespace", "Namespace Of The Tasks").Short('n').Default("default").String(),
+	}
+}
+
+func (f *apiFlags) client() *apiclient.Client {
+	return apiclient.New(*f.server, *f.namespace, *f.apiKey, *f.token)
+}
@@ -13,12 +13,17 @@ import (
 	config "scheduler/config"
 	http "scheduler/http"
 	handlers "scheduler/http/handlers"
+	models "scheduler/models"
 	mongodb "scheduler/repositories/mongodb"
+	auth "scheduler/services/auth"
+	executer "scheduler/services/executer"
 	health "scheduler/services/health"
+	quota "scheduler/services/quota"
 	scheduler "scheduler/services/scheduler"
 	helpers "scheduler/utils/helpers"
 	httpclient "scheduler/utils/httpclient"
 	notifications "scheduler/utils/notifications"
+	secrets "scheduler/utils/secrets"
 
 	// External Packages
 	"github.com/alecthomas/kingpin/v2"
@@ -43,16 +48,61 @@ func InitializeServer(ctx context.Context, k config.Config, logger *zap.Logger)
 	// Initialize Slack alert sender
 	slackAlerter := notifications.NewSlackSender(k.Slack, k.IsProdMode)
 
-	// Initialize shared HTTP client with connection pool
-	httpClient := httpclient.New()
+	// Initialize outbound destination policy and the shared HTTP client with connection pool
+	outboundPolicy, err := httpclient.NewPolicy(k.Outbound)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbound policy: %w", err)
+	}
+	httpClient := httpclient.New(outboundPolicy)
+
+	// Initialize secret provider used to resolve {{secret "name"}} references
+	secretProvider, err := secrets.NewProvider(k.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets provider: %w", err)
+	}
+	secretResolver := secrets.NewResolver(secretProvider)
 
 	// Wire repositories, services and handlers
 	schedulerRepo := mongodb.NewSchedulerRepository(mongoClient)
+	if err = schedulerRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create task indexes: %w", err)
+	}
+	apiKeyRepo := mongodb.NewAPIKeyRepository(mongoClient)
+	if err = apiKeyRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create api key indexes: %w", err)
+	}
+	usageRepo := mongodb.NewUsageRepository(mongoClient)
+	if err = usageRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create usage indexes: %w", err)
+	}
+	jobRepo := mongodb.NewJobRepository(mongoClient)
+	if err = jobRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create job indexes: %w", err)
+	}
+	deadLetterRepo := mongodb.NewDeadLetterRepository(mongoClient, k.DeadLetters.Retention)
+	if err = deadLetterRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create dead letter indexes: %w", err)
+	}
+	completionRepo := mongodb.NewCompletionRepository(mongoClient)
+	if err = completionRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create pending completion indexes: %w", err)
+	}
+	rateLimiter, err := newRateLimiter(ctx, k.RateLimits, mongoClient)
+	if err != nil {
+		return nil, err
+	}
+
 	healthSVC := health.NewService(mongoClient)
-	schedulerSVC := scheduler.NewService(logger, schedulerRepo, slackAlerter, httpClient)
+	authSVC := auth.NewService(logger, apiKeyRepo, k.Auth)
+	quotaSVC := quota.NewService(schedulerRepo, usageRepo, k.Namespaces)
+	schedulerSVC := scheduler.NewService(logger, schedulerRepo, slackAlerter, httpClient, secretResolver, quotaSVC,
+		executer.NewPool(k.Execution), rateLimiter, executer.NewBreaker(k.Breaker, logger, slackAlerter), deadLetterRepo,
+		completionRepo, k.Callbacks.Endpoint(k.Prefix), jobRepo, k.Scheduler)
 
 	closeCallback := func() {
-		schedulerSVC.Stop()
+		drainCtx, cancel := context.WithTimeout(context.Background(), k.Scheduler.DrainTimeout)
+		defer cancel()
+		schedulerSVC.Stop(drainCtx)
 		_ = mongoClient.Close()
 		logger.Info("Server Stopped Successfully")
 	}
@@ -62,20 +112,39 @@ func InitializeServer(ctx context.Context, k config.Config, logger *zap.Logger)
 		logger.Fatal("Cannot Start Scheduler!", zap.Error(err))
 	}
 
-	schedulerHandler := handlers.NewSchedulerHandler(schedulerSVC)
-	server := http.NewServer(logger, k.Prefix, healthSVC, schedulerHandler, closeCallback)
+	schedulerHandler := handlers.NewSchedulerHandler(schedulerSVC, models.TaskRules{
+		Policy:    outboundPolicy,
+		Callbacks: k.Callbacks.BaseURL != "",
+	})
+	apiKeyHandler := handlers.NewAPIKeyHandler(authSVC)
+	deadLetterHandler := handlers.NewDeadLetterHandler(schedulerSVC)
+	callbackHandler := handlers.NewCallbackHandler(schedulerSVC)
+	server := http.NewServer(logger, k.Prefix, healthSVC, authSVC, schedulerHandler, apiKeyHandler, deadLetterHandler, callbackHandler, closeCallback)
 	return server, nil
 
 }
 
+// newRateLimiter builds the outbound rate limiter with the configured store.
+func newRateLimiter(ctx context.Context, cfg config.RateLimits, mongoClient *mongodb.Client) (*executer.RateLimiter, error) {
+	if cfg.Store != config.RateLimitMongo {
+		return executer.NewRateLimiter(cfg, executer.NewMemoryRateStore()), nil
+	}
+	rateLimitRepo := mongodb.NewRateLimitRepository(mongoClient)
+	if err := rateLimitRepo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create rate limit indexes: %w", err)
+	}
+	return executer.NewRateLimiter(cfg, rateLimitRepo), nil
+}
+
+var (
+	configPath = kingpin.Flag("config", "Path To The Application Config File").
+			Short('c').Default("config.yml").String()
+	serveCmd = kingpin.Command("serve", "Run The Scheduler API Server").Default()
+)
+
 // LoadConfig loads the default configuration and overrides it with the config file
 // specified by the --config flag.
 func LoadConfig() *koanf.Koanf {
-	configPath := kingpin.Flag("config", "Path To The Application Config File").
-		Short('c').Default("config.yml").String()
-
-	kingpin.Parse()
-
 	k := koanf.New(".")
 	_ = k.Load(rawbytes.Provider(config.DefaultConfig), yaml.Parser())
 	if *configPath != "" {
@@ -103,27 +172,25 @@ func NewLogger(k config.Config) *zap.Logger {
 	return logger
 }
 
-// main is the entrypoint that loads config, sets up logging,
-// and starts the HTTP server with graceful shutdown.
+// main dispatches to the selected subcommand. The default, serve, loads config,
+// sets up logging, and starts the HTTP server with graceful shutdown.
 func main() {
-	k := LoadConfig()
-
-	// Unmarshal Config
-	appKonf := config.Config{}
-	if err := k.Unmarshal("", &appKonf); err != nil {
-		log.Fatalf("Error Loading Config: %v", err)
-	}
-
-	// Validate Config
-	if err := appKonf.Validate(); err != nil {
-		helpers.LogValidationErrors(err)
-		log.Fatalf("Invalid Configuration")
+	switch kingpin.Parse() {
+	case syncCmd.FullCommand():
+		os.Exit(runSync())
+	case exportCmd.FullCommand():
+		os.Exit(runExport())
+	case importCmd.FullCommand():
+		os.Exit(runImport())
+	case workerCmd.FullCommand():
+		runWorker()
+	case serveCmd.FullCommand():
+		serve()
 	}
+}
 
-	// Print Config in Dev Mode
-	if !appKonf.IsProdMode {
-		helpers.PrintStruct(appKonf)
-	}
+func serve() {
+	appKonf := loadAppConfig(config.Config.Validate)
 
 	// Initialize Logger
 	logger := NewLogger(appKonf)
@@ -143,3 +210,27 @@ func main() {
 		logger.Fatal("Cannot Listen On Port", zap.Error(err))
 	}
 }
+
+// loadAppConfig loads, validates with validate and, in dev mode, prints the
+// configuration, exiting the process if it is invalid.
+func loadAppConfig(validate func(config.Config) error) config.Config {
+	k := LoadConfig()
+
+	// Unmarshal Config
+	appKonf := config.Config{}
+	if err := k.Unmarshal("", &appKonf); err != nil {
+		log.Fatalf("Error Loading Config: %v", err)
+	}
+
+	// Validate Config
+	if err := validate(appKonf); err != nil {
+		helpers.LogValidationErrors(err)
+		log.Fatalf("Invalid Configuration")
+	}
+
+	// Print Config in Dev Mode
+	if !appKonf.IsProdMode {
+		helpers.PrintStruct(appKonf)
+	}
+	return appKonf
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	// Local Packages
+	tasksync "scheduler/services/tasksync"
+
+	// External Packages
+	"github.com/alecthomas/kingpin/v2"
+)
+
+var (
+	syncCmd    = kingpin.Command("sync", "Sync Tasks With The YAML Definitions In A Directory")
+	syncAPI    = newAPIFlags(syncCmd)
+	syncDir    = syncCmd.Arg("dir", "Directory Of YAML Task Definitions").Required().ExistingDir()
+	syncDryRun = syncCmd.Flag("dry-run", "Print The Plan Without Applying It").Bool()
+	syncPrune  = syncCmd.Flag("prune", "Delete Synced Tasks That Are No Longer Defined Instead Of Disabling Them").Bool()
+)
+
+// runSync diffs the definitions against the server and applies the plan,
+// returning the process exit code.
+func runSync() int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	defs, err := tasksync.LoadDir(*syncDir)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	client := syncAPI.client()
+	existing, err := client.ListTasks(ctx, tasksync.ManagedSelector)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "failed to list tasks:", err)
+		return 1
+	}
+
+	plan := tasksync.BuildPlan(defs, existing, *syncPrune)
+	plan.Print(os.Stdout)
+	if *syncDryRun || len(plan.Changes) == 0 {
+		return 0
+	}
+
+	if err := tasksync.Apply(ctx, client, plan, os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	// Local Packages
+	models "scheduler/models"
+
+	// External Packages
+	"github.com/alecthomas/kingpin/v2"
+)
+
+var (
+	exportCmd      = kingpin.Command("export", "Export Tasks As JSON Lines Or YAML")
+	exportAPI      = newAPIFlags(exportCmd)
+	exportFormat   = exportCmd.Flag("format", "Output Format").Default("jsonl").Enum("jsonl", "yaml")
+	exportSelector = exportCmd.Flag("selector", "Label Selector Of The Tasks To Export").Short('l').String()
+	exportOutput   = exportCmd.Flag("output", "Output File, Stdout By Default").Short('o').String()
+
+	importCmd         = kingpin.Command("import", "Import Tasks From An Export File")
+	importAPI         = newAPIFlags(importCmd)
+	importFile        = importCmd.Arg("file", "Export File To Import").Required().ExistingFile()
+	importFormat      = importCmd.Flag("format", "Input Format, Detected From The File Extension By Default").Enum("jsonl", "yaml")
+	importMode        = importCmd.Flag("mode", "Import Mode").Default(string(models.BatchAtomic)).Enum(string(models.BatchAtomic), string(models.BatchBestEffort))
+	importPreserveIDs = importCmd.Flag("preserve-ids", "Keep The Exported Task IDs").Bool()
+	importEnable      = importCmd.Flag("enable", "Import Tasks Enabled Instead Of Disabled").Bool()
+	importRewrites    = importCmd.Flag("rewrite-host", "Rewrite A Target URL Host, As from=to (Repeatable)").Strings()
+)
+
+// runExport writes the exported tasks to the output file or stdout,
+// returning the process exit code.
+func runExport() int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var out io.Writer = os.Stdout
+	if *exportOutput != "" {
+		f, err := os.Create(*exportOutput)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	format := models.TransferFormat(*exportFormat)
+	if err := exportAPI.client().ExportTasks(ctx, format, *exportSelector, out); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "failed to export tasks:", err)
+		return 1
+	}
+	return 0
+}
+
+// runImport uploads the export file and prints the per-task results,
+// returning the process exit code.
+func runImport() int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := models.ImportOptions{
+		Mode:         models.BatchMode(*importMode),
+		PreserveIDs:  *importPreserveIDs,
+		Enable:       *importEnable,
+		HostRewrites: make(map[string]string),
+	}
+	for _, rule := range *importRewrites {
+		from, to, err := models.ParseHostRewrite(rule)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		opts.HostRewrites[from] = to
+	}
+
+	format := models.TransferFormat(*importFormat)
+	if format == "" {
+		format = models.FormatJSONL
+		if ext := strings.ToLower(filepath.Ext(*importFile)); ext == ".yml" || ext == ".yaml" {
+			format = models.FormatYAML
+		}
+	}
+
+	f, err := os.Open(*importFile)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer func() { _ = f.Close() }()
+
+	res, err := importAPI.client().ImportTasks(ctx, format, f, opts)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "failed to import tasks:", err)
+		return 1
+	}
+	for _, item := range res.Results {
+		switch {
+		case item.TaskID != "":
+			fmt.Printf("imported task %d as %s\n", item.Index, item.TaskID)
+		case item.Error != "":
+			fmt.Printf("failed to import task %d: %s\n", item.Index, item.Error)
+		default:
+			for _, fe := range item.ValidationErrors {
+				fmt.Printf("failed to import task %d: %s: %s\n", item.Index, fe.Field, fe.Error)
+			}
+		}
+	}
+	fmt.Printf("Imported %d, failed %d.\n", res.Created, res.Failed)
+	if res.Failed > 0 {
+		return 1
+	}
+	return 0
+}
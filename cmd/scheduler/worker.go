@@ -0,0 +1,104 @@
+package main
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	// Local Packages
+	config "scheduler/config"
+	mongodb "scheduler/repositories/mongodb"
+	executer "scheduler/services/executer"
+	quota "scheduler/services/quota"
+	scheduler "scheduler/services/scheduler"
+	httpclient "scheduler/utils/httpclient"
+	notifications "scheduler/utils/notifications"
+	secrets "scheduler/utils/secrets"
+
+	// External Packages
+	"github.com/alecthomas/kingpin/v2"
+	"go.uber.org/zap"
+)
+
+var (
+	workerCmd         = kingpin.Command("worker", "Run A Worker That Executes Queued Task Fires")
+	workerConcurrency = workerCmd.Flag("concurrency", "Number Of Jobs Executed At Once (Overrides worker.concurrency)").Int()
+)
+
+// InitializeWorker wires a queue worker: MongoDB → Repositories → Services → Worker.
+// The returned function closes the database connection.
+func InitializeWorker(ctx context.Context, k config.Config, logger *zap.Logger) (*scheduler.Worker, func(), error) {
+	mongoClient, err := mongodb.Connect(ctx, logger, k.Mongo.URI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect mongo: %w", err)
+	}
+	closeMongo := func() { _ = mongoClient.Close() }
+
+	slackAlerter := notifications.NewSlackSender(k.Slack, k.IsProdMode)
+	outboundPolicy, err := httpclient.NewPolicy(k.Outbound)
+	if err != nil {
+		closeMongo()
+		return nil, nil, fmt.Errorf("failed to create outbound policy: %w", err)
+	}
+	secretProvider, err := secrets.NewProvider(k.Secrets)
+	if err != nil {
+		closeMongo()
+		return nil, nil, fmt.Errorf("failed to create secrets provider: %w", err)
+	}
+
+	schedulerRepo := mongodb.NewSchedulerRepository(mongoClient)
+	usageRepo := mongodb.NewUsageRepository(mongoClient)
+	jobRepo := mongodb.NewJobRepository(mongoClient)
+	if err = jobRepo.EnsureIndexes(ctx); err != nil {
+		closeMongo()
+		return nil, nil, fmt.Errorf("failed to create job indexes: %w", err)
+	}
+	deadLetterRepo := mongodb.NewDeadLetterRepository(mongoClient, k.DeadLetters.Retention)
+	if err = deadLetterRepo.EnsureIndexes(ctx); err != nil {
+		closeMongo()
+		return nil, nil, fmt.Errorf("failed to create dead letter indexes: %w", err)
+	}
+	completionRepo := mongodb.NewCompletionRepository(mongoClient)
+	if err = completionRepo.EnsureIndexes(ctx); err != nil {
+		closeMongo()
+		return nil, nil, fmt.Errorf("failed to create pending completion indexes: %w", err)
+	}
+	rateLimiter, err := newRateLimiter(ctx, k.RateLimits, mongoClient)
+	if err != nil {
+		closeMongo()
+		return nil, nil, err
+	}
+
+	quotaSVC := quota.NewService(schedulerRepo, usageRepo, k.Namespaces)
+	worker := scheduler.NewWorker(logger, schedulerRepo, jobRepo, slackAlerter,
+		httpclient.New(outboundPolicy), secrets.NewResolver(secretProvider), quotaSVC, executer.NewPool(k.Execution), rateLimiter, executer.NewBreaker(k.Breaker, logger, slackAlerter), deadLetterRepo,
+		completionRepo, k.Callbacks.Endpoint(k.Prefix), k.Worker)
+	return worker, closeMongo, nil
+}
+
+// runWorker executes queued fires until interrupted, then drains the runs in flight.
+func runWorker() {
+	appKonf := loadAppConfig(config.Config.ValidateWorker)
+	if *workerConcurrency > 0 {
+		appKonf.Worker.Concurrency = *workerConcurrency
+	}
+
+	logger := NewLogger(appKonf)
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	worker, closeMongo, err := InitializeWorker(ctx, appKonf, logger)
+	if err != nil {
+		logger.Fatal("Cannot Initialize Worker", zap.Error(err))
+	}
+	defer closeMongo()
+
+	worker.Run(ctx, appKonf.Scheduler.DrainTimeout)
+}
@@ -1,6 +1,13 @@
 package config
 
 import (
+	// Go Internal Packages
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
 	// Local Packages
 	errors "scheduler/errors"
 	helpers "scheduler/utils/helpers"
@@ -22,19 +29,102 @@ is_prod_mode: false
 mongo:
   uri: "mongodb://localhost:27017"
 
+scheduler:
+  execution_mode: "inline"
+  poll_interval: "1s"
+  claim_batch_size: 100
+  drain_timeout: "30s"
+  callback_sweep_interval: "10s"
+  claim_lease: "2m"
+
+execution:
+  max_concurrent: 100
+  max_per_host: 10
+  max_queue_wait: "5m"
+
+rate_limits:
+  store: "memory"
+  hosts: []
+
+circuit_breaker:
+  enabled: true
+  failure_threshold: 5
+  open_timeout: "1m"
+  half_open_probes: 1
+
+dead_letters:
+  retention: "720h"
+
+callbacks:
+  base_url: ""
+
+worker:
+  concurrency: 10
+  poll_interval: "1s"
+  visibility_timeout: "5m"
+  heartbeat_interval: "30s"
+  max_deliveries: 3
+
 slack:
  webhook_url: "https://hooks.slack.com/services/your/webhook/url"
  send_alerts_in_dev: false
+
+auth:
+  enabled: false
+  bootstrap_key: ""
+  oidc:
+    issuers: []
+    jwks_cache_ttl: "1h"
+    leeway: "30s"
+    role_scopes: {}
+
+namespaces:
+  default_quota:
+    max_tasks: 0
+    max_executions_per_hour: 0
+  quotas: {}
+
+outbound:
+  allowed_schemes: ["http", "https"]
+  allow_hosts: []
+  deny_hosts: []
+  allow_cidrs: []
+  deny_cidrs: []
+  block_private: true
+
+secrets:
+  provider: "env"
+  env_prefix: "SCHEDULER_SECRET_"
+  file_dir: "/etc/scheduler/secrets"
+  vault:
+    address: "http://localhost:8200"
+    token: ""
+    token_file: ""
+    mount: "secret"
+    path: "scheduler"
+    field: "value"
+    cache_ttl: "5m"
 `)
 
 type Config struct {
-	Application string `koanf:"application"`
-	Logger      Logger `koanf:"logger"`
-	Listen      string `koanf:"listen"`
-	Prefix      string `koanf:"prefix"`
-	IsProdMode  bool   `koanf:"is_prod_mode"`
-	Mongo       Mongo  `koanf:"mongo"`
-	Slack       Slack  `koanf:"slack"`
+	Application string      `koanf:"application"`
+	Logger      Logger      `koanf:"logger"`
+	Listen      string      `koanf:"listen"`
+	Prefix      string      `koanf:"prefix"`
+	IsProdMode  bool        `koanf:"is_prod_mode"`
+	Mongo       Mongo       `koanf:"mongo"`
+	Scheduler   Scheduler   `koanf:"scheduler"`
+	Execution   Execution   `koanf:"execution"`
+	RateLimits  RateLimits  `koanf:"rate_limits"`
+	Breaker     Breaker     `koanf:"circuit_breaker"`
+	DeadLetters DeadLetters `koanf:"dead_letters"`
+	Callbacks   Callbacks   `koanf:"callbacks"`
+	Worker      Worker      `koanf:"worker"`
+	Slack       Slack       `koanf:"slack"`
+	Auth        Auth        `koanf:"auth"`
+	Namespaces  Namespaces  `koanf:"namespaces"`
+	Outbound    Outbound    `koanf:"outbound"`
+	Secrets     Secrets     `koanf:"secrets"`
 }
 
 type Logger struct {
@@ -46,13 +136,190 @@ type Mongo struct {
 	URI string `koanf:"uri"`
 }
 
+// Execution modes of the scheduler.
+const (
+	// ExecutionInline runs fires in the process that dispatches them.
+	ExecutionInline = "inline"
+	// ExecutionQueue enqueues fires for `scheduler worker` processes.
+	ExecutionQueue = "queue"
+)
+
+// Scheduler configures the scheduling engine.
+type Scheduler struct {
+	ExecutionMode string `koanf:"execution_mode"`
+	// PollInterval is how often the dispatcher looks for due tasks.
+	PollInterval time.Duration `koanf:"poll_interval"`
+	// ClaimBatchSize is how many due tasks the dispatcher fetches per query.
+	ClaimBatchSize int `koanf:"claim_batch_size"`
+	// DrainTimeout is how long running executions may take to finish on shutdown
+	// before they are cancelled.
+	DrainTimeout time.Duration `koanf:"drain_timeout"`
+	// CallbackSweepInterval is how often runs waiting for a callback are
+	// checked for an elapsed timeout.
+	CallbackSweepInterval time.Duration `koanf:"callback_sweep_interval"`
+	// ClaimLease is how long the claim of a fire run in process outlives the
+	// last refresh by its instance, after which the fire is given back.
+	ClaimLease time.Duration `koanf:"claim_lease"`
+}
+
+// Execution bounds how many task executions run at once in a process. Fires
+// beyond the limits wait in a queue for up to MaxQueueWait. Zero limits mean unlimited.
+type Execution struct {
+	MaxConcurrent int           `koanf:"max_concurrent"`
+	MaxPerHost    int           `koanf:"max_per_host"`
+	MaxQueueWait  time.Duration `koanf:"max_queue_wait"`
+}
+
+// Rate limit stores.
+const (
+	// RateLimitMemory keeps rate limit state in the process, so each replica
+	// enforces the limits on its own.
+	RateLimitMemory = "memory"
+	// RateLimitMongo keeps rate limit state in MongoDB, shared by every replica.
+	RateLimitMongo = "mongo"
+)
+
+// RateLimits throttles outbound requests per target host.
+type RateLimits struct {
+	Store string          `koanf:"store"`
+	Hosts []HostRateLimit `koanf:"hosts"`
+}
+
+// HostRateLimit allows Requests requests every Per to the hosts matching Host,
+// an exact name or a "*.example.com" wildcard. Every matching host shares the
+// limit. Burst defaults to 1.
+type HostRateLimit struct {
+	Host     string        `koanf:"host"`
+	Requests int           `koanf:"requests"`
+	Per      time.Duration `koanf:"per"`
+	Burst    int           `koanf:"burst"`
+}
+
+// Breaker configures the circuit breaker kept per target host. A circuit
+// opens after FailureThreshold consecutive failed attempts, short-circuits
+// runs for OpenTimeout, then lets HalfOpenProbes attempts through at once; the
+// first that succeeds closes it again and any that fails reopens it.
+type Breaker struct {
+	Enabled          bool          `koanf:"enabled"`
+	FailureThreshold int           `koanf:"failure_threshold"`
+	OpenTimeout      time.Duration `koanf:"open_timeout"`
+	HalfOpenProbes   int           `koanf:"half_open_probes"`
+}
+
+// DeadLetters configures the store of executions that exhausted their attempts.
+type DeadLetters struct {
+	// Retention is how long a dead letter is kept before it is deleted.
+	Retention time.Duration `koanf:"retention"`
+}
+
+// Callbacks configures asynchronous completion through callbacks.
+type Callbacks struct {
+	// BaseURL is the public URL targets reach this API on, without the prefix.
+	BaseURL string `koanf:"base_url"`
+}
+
+// Endpoint returns the URL callback IDs are appended to, or "" when no base
+// URL is configured.
+func (c Callbacks) Endpoint(prefix string) string {
+	if c.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(c.BaseURL, "/") + prefix + "/v1/callbacks/"
+}
+
+// Worker configures `scheduler worker` processes, which execute queued fires.
+type Worker struct {
+	Concurrency  int           `koanf:"concurrency"`
+	PollInterval time.Duration `koanf:"poll_interval"`
+	// VisibilityTimeout is how long a leased job stays hidden from other
+	// workers without a heartbeat.
+	VisibilityTimeout time.Duration `koanf:"visibility_timeout"`
+	HeartbeatInterval time.Duration `koanf:"heartbeat_interval"`
+	// MaxDeliveries is how many times a job is leased before it is given up.
+	MaxDeliveries int `koanf:"max_deliveries"`
+}
+
 type Slack struct {
 	WebhookURL     string `koanf:"webhook_url"`
 	SendAlertInDev bool   `koanf:"send_alerts_in_dev"`
 }
 
+type Auth struct {
+	Enabled      bool   `koanf:"enabled"`
+	BootstrapKey string `koanf:"bootstrap_key"`
+	OIDC         OIDC   `koanf:"oidc"`
+}
+
+type OIDC struct {
+	Issuers      []Issuer            `koanf:"issuers"`
+	JWKSCacheTTL time.Duration       `koanf:"jwks_cache_ttl"`
+	Leeway       time.Duration       `koanf:"leeway"`
+	RoleScopes   map[string][]string `koanf:"role_scopes"`
+}
+
+// Scopes lists the scopes role_scopes may grant. It matches models.Scope,
+// which config cannot import.
+var Scopes = []string{"read", "write", "execute", "admin"}
+
+type Issuer struct {
+	Issuer          string `koanf:"issuer"`
+	Audience        string `koanf:"audience"`
+	JWKSURL         string `koanf:"jwks_url"`
+	JWKSFile        string `koanf:"jwks_file"`
+	SubjectClaim    string `koanf:"subject_claim"`
+	RolesClaim      string `koanf:"roles_claim"`
+	NamespacesClaim string `koanf:"namespaces_claim"`
+}
+
+type Namespaces struct {
+	DefaultQuota Quota            `koanf:"default_quota"`
+	Quotas       map[string]Quota `koanf:"quotas"`
+}
+
+// Quota limits a namespace. Zero values mean unlimited.
+type Quota struct {
+	MaxTasks             int `koanf:"max_tasks"`
+	MaxExecutionsPerHour int `koanf:"max_executions_per_hour"`
+}
+
+type Outbound struct {
+	AllowedSchemes []string `koanf:"allowed_schemes"`
+	AllowHosts     []string `koanf:"allow_hosts"`
+	DenyHosts      []string `koanf:"deny_hosts"`
+	AllowCIDRs     []string `koanf:"allow_cidrs"`
+	DenyCIDRs      []string `koanf:"deny_cidrs"`
+	BlockPrivate   bool     `koanf:"block_private"`
+}
+
+type Secrets struct {
+	Provider  string `koanf:"provider"`
+	EnvPrefix string `koanf:"env_prefix"`
+	FileDir   string `koanf:"file_dir"`
+	Vault     Vault  `koanf:"vault"`
+}
+
+type Vault struct {
+	Address   string        `koanf:"address"`
+	Token     string        `koanf:"token"`
+	TokenFile string        `koanf:"token_file"`
+	Mount     string        `koanf:"mount"`
+	Path      string        `koanf:"path"`
+	Field     string        `koanf:"field"`
+	CacheTTL  time.Duration `koanf:"cache_ttl"`
+}
+
 // Validate validates the configuration
 func (c Config) Validate() error {
+	return c.validate(true)
+}
+
+// ValidateWorker validates the configuration of `scheduler worker`, which
+// serves no API and so ignores the auth section.
+func (c Config) ValidateWorker() error {
+	return c.validate(false)
+}
+
+func (c Config) validate(serveAPI bool) error {
 	ve := errors.ValidationErrs()
 
 	// Required Fields
@@ -63,5 +330,127 @@ func (c Config) Validate() error {
 	helpers.ValidateRequiredString(ve, "mongo.uri", c.Mongo.URI)
 	helpers.ValidateRequiredString(ve, "slack.webhook_url", c.Slack.WebhookURL)
 
+	if c.Scheduler.ExecutionMode != ExecutionInline && c.Scheduler.ExecutionMode != ExecutionQueue {
+		ve.Add("scheduler.execution_mode", "must be one of inline, queue")
+	}
+	if c.Scheduler.PollInterval <= 0 {
+		ve.Add("scheduler.poll_interval", "must be positive")
+	}
+	if c.Scheduler.ClaimBatchSize <= 0 {
+		ve.Add("scheduler.claim_batch_size", "must be positive")
+	}
+	if c.Scheduler.DrainTimeout < 0 {
+		ve.Add("scheduler.drain_timeout", "must not be negative")
+	}
+	if c.Scheduler.CallbackSweepInterval <= 0 {
+		ve.Add("scheduler.callback_sweep_interval", "must be positive")
+	}
+	if c.Scheduler.ClaimLease < 4*time.Second {
+		ve.Add("scheduler.claim_lease", "must be at least 4s")
+	}
+
+	if c.Execution.MaxConcurrent < 0 {
+		ve.Add("execution.max_concurrent", "must not be negative")
+	}
+	if c.Execution.MaxPerHost < 0 {
+		ve.Add("execution.max_per_host", "must not be negative")
+	}
+	if c.Execution.MaxQueueWait <= 0 {
+		ve.Add("execution.max_queue_wait", "must be positive")
+	}
+
+	if c.RateLimits.Store != RateLimitMemory && c.RateLimits.Store != RateLimitMongo {
+		ve.Add("rate_limits.store", "must be one of memory, mongo")
+	}
+	for i, rl := range c.RateLimits.Hosts {
+		field := fmt.Sprintf("rate_limits.hosts[%d]", i)
+		helpers.ValidateRequiredString(ve, field+".host", rl.Host)
+		if rl.Requests <= 0 {
+			ve.Add(field+".requests", "must be positive")
+		}
+		if rl.Per <= 0 {
+			ve.Add(field+".per", "must be positive")
+		}
+		if rl.Burst < 0 {
+			ve.Add(field+".burst", "must not be negative")
+		}
+	}
+
+	if c.Breaker.Enabled {
+		if c.Breaker.FailureThreshold <= 0 {
+			ve.Add("circuit_breaker.failure_threshold", "must be positive")
+		}
+		if c.Breaker.OpenTimeout <= 0 {
+			ve.Add("circuit_breaker.open_timeout", "must be positive")
+		}
+		if c.Breaker.HalfOpenProbes <= 0 {
+			ve.Add("circuit_breaker.half_open_probes", "must be positive")
+		}
+	}
+
+	if c.Callbacks.BaseURL != "" {
+		if u, err := url.Parse(c.Callbacks.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			ve.Add("callbacks.base_url", "must be an absolute http or https url")
+		}
+	}
+
+	if c.DeadLetters.Retention <= 0 {
+		ve.Add("dead_letters.retention", "must be positive")
+	}
+
+	if c.Worker.Concurrency <= 0 {
+		ve.Add("worker.concurrency", "must be positive")
+	}
+	if c.Worker.PollInterval <= 0 {
+		ve.Add("worker.poll_interval", "must be positive")
+	}
+	if c.Worker.HeartbeatInterval <= 0 || c.Worker.VisibilityTimeout <= c.Worker.HeartbeatInterval {
+		ve.Add("worker.visibility_timeout", "must be longer than a positive heartbeat_interval")
+	}
+	if c.Worker.MaxDeliveries <= 0 {
+		ve.Add("worker.max_deliveries", "must be positive")
+	}
+
+	if serveAPI {
+		// Without either there is no way to create the first API key, and every
+		// request would be rejected.
+		if c.Auth.Enabled && c.Auth.BootstrapKey == "" && len(c.Auth.OIDC.Issuers) == 0 {
+			ve.Add("auth.bootstrap_key", "is required when auth is enabled without oidc issuers")
+		}
+		for i, iss := range c.Auth.OIDC.Issuers {
+			field := fmt.Sprintf("auth.oidc.issuers[%d]", i)
+			helpers.ValidateRequiredString(ve, field+".issuer", iss.Issuer)
+			helpers.ValidateRequiredString(ve, field+".audience", iss.Audience)
+			if iss.JWKSURL != "" && iss.JWKSFile != "" {
+				ve.Add(field, "only one of jwks_url and jwks_file may be set")
+			}
+		}
+
+		for role, scopes := range c.Auth.OIDC.RoleScopes {
+			for _, scope := range scopes {
+				if !slices.Contains(Scopes, scope) {
+					ve.Add("auth.oidc.role_scopes."+role, fmt.Sprintf("unknown scope %q, must be one of %s", scope, strings.Join(Scopes, ", ")))
+				}
+			}
+		}
+	}
+
+	helpers.ValidateRequiredSlice(ve, "outbound.allowed_schemes", c.Outbound.AllowedSchemes)
+
+	switch c.Secrets.Provider {
+	case "env":
+	case "file":
+		helpers.ValidateRequiredString(ve, "secrets.file_dir", c.Secrets.FileDir)
+	case "vault":
+		helpers.ValidateRequiredString(ve, "secrets.vault.address", c.Secrets.Vault.Address)
+		helpers.ValidateRequiredString(ve, "secrets.vault.mount", c.Secrets.Vault.Mount)
+		helpers.ValidateRequiredString(ve, "secrets.vault.field", c.Secrets.Vault.Field)
+		if c.Secrets.Vault.Token == "" && c.Secrets.Vault.TokenFile == "" {
+			ve.Add("secrets.vault.token", "token or token_file is required")
+		}
+	default:
+		ve.Add("secrets.provider", "must be one of env, file, vault")
+	}
+
 	return ve.Err()
 }
@@ -74,6 +74,7 @@ func NewError(kind Kind, message string, wrapped ...error) error {
 }
 
 var (
-	As = errors.As
-	Is = errors.Is
+	As  = errors.As
+	Is  = errors.Is
+	New = errors.New
 )
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	// Go Internal Packages
+	"context"
+	"encoding/json"
+	"net/http"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+
+	// External Packages
+	"github.com/go-chi/chi/v5"
+)
+
+type APIKeyService interface {
+	CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeKey(ctx context.Context, keyID string) error
+}
+
+type APIKeyHandler struct {
+	apiKeyService APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+func (h *APIKeyHandler) Create(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	var req models.CreateAPIKeyRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	if err = req.Validate(); err != nil {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(err)
+	}
+
+	key, err := h.apiKeyService.CreateKey(r.Context(), req)
+	if err == nil {
+		return key, http.StatusCreated, nil
+	}
+	return
+}
+
+func (h *APIKeyHandler) List(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	keys, err := h.apiKeyService.ListKeys(r.Context())
+	if err == nil {
+		return map[string]any{"keys": keys}, http.StatusOK, nil
+	}
+	return
+}
+
+func (h *APIKeyHandler) Revoke(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	keyID := chi.URLParam(r, "key_id")
+	if keyID == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("key_id")
+	}
+
+	err = h.apiKeyService.RevokeKey(r.Context(), keyID)
+	if err == nil {
+		return map[string]any{
+			"message": "API Key Revoked Successfully",
+			"key_id":  keyID,
+		}, http.StatusOK, nil
+	}
+	return
+}
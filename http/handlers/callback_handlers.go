@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	// Go Internal Packages
+	"context"
+	"encoding/json"
+	"net/http"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+
+	// External Packages
+	"github.com/go-chi/chi/v5"
+)
+
+type CallbackService interface {
+	CompleteCallback(ctx context.Context, id, token string, result models.CallbackResult) error
+	ListWaiting(ctx context.Context, namespace, taskID string) (*models.PendingCompletionList, error)
+}
+
+type CallbackHandler struct {
+	callbackService CallbackService
+}
+
+func NewCallbackHandler(callbackService CallbackService) *CallbackHandler {
+	return &CallbackHandler{callbackService: callbackService}
+}
+
+// Complete receives the result of a waiting run from its target. It is not
+// behind API key authentication: the one-time token in the
+// X-Scheduler-Callback-Token header authorizes the call.
+func (h *CallbackHandler) Complete(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	id := chi.URLParam(r, "callback_id")
+	if id == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("callback_id")
+	}
+	token := r.Header.Get(models.CallbackTokenHeader)
+	if token == "" {
+		return nil, http.StatusUnauthorized, errors.NewError(errors.Unauthorized, "missing callback token")
+	}
+	var result models.CallbackResult
+	if err = json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	if err = result.Validate(); err != nil {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(err)
+	}
+
+	err = h.callbackService.CompleteCallback(r.Context(), id, token, result)
+	if err == nil {
+		return map[string]any{
+			"message":     "Run Completed Successfully",
+			"callback_id": id,
+		}, http.StatusOK, nil
+	}
+	return
+}
+
+// ListWaiting lists runs waiting for their callback, filtered by ?task_id=
+// when given.
+func (h *CallbackHandler) ListWaiting(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	waiting, err := h.callbackService.ListWaiting(r.Context(), namespaceParam(r), r.URL.Query().Get("task_id"))
+	if err == nil {
+		return waiting, http.StatusOK, nil
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	// Go Internal Packages
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+
+	// External Packages
+	"github.com/go-chi/chi/v5"
+)
+
+type DeadLetterService interface {
+	ListDeadLetters(ctx context.Context, namespace, taskID string, limit, skip int64) (*models.DeadLetterList, error)
+	GetDeadLetter(ctx context.Context, namespace, id string) (*models.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, namespace, id string, edit models.ReplayRequest) error
+	DiscardDeadLetter(ctx context.Context, namespace, id string) error
+}
+
+type DeadLetterHandler struct {
+	deadLetterService DeadLetterService
+}
+
+func NewDeadLetterHandler(deadLetterService DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: deadLetterService}
+}
+
+// List lists dead letters, filtered by ?task_id= when given.
+func (h *DeadLetterHandler) List(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	limit, err := intQueryParam(r, "limit", defaultListLimit)
+	if err != nil || limit <= 0 || limit > maxListLimit {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "limit must be between 1 and 1000")
+	}
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "offset must be a non-negative integer")
+	}
+
+	dls, err := h.deadLetterService.ListDeadLetters(r.Context(), namespaceParam(r), r.URL.Query().Get("task_id"), limit, offset)
+	if err == nil {
+		return dls, http.StatusOK, nil
+	}
+	return
+}
+
+func (h *DeadLetterHandler) GetOne(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	id := chi.URLParam(r, "dead_letter_id")
+	if id == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("dead_letter_id")
+	}
+
+	dl, err := h.deadLetterService.GetDeadLetter(r.Context(), namespaceParam(r), id)
+	if err == nil {
+		return dl, http.StatusOK, nil
+	}
+	return
+}
+
+// Replay sends the dead-lettered request again. An optional body replaces its
+// headers, query params or request body.
+func (h *DeadLetterHandler) Replay(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	id := chi.URLParam(r, "dead_letter_id")
+	if id == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("dead_letter_id")
+	}
+	var edit models.ReplayRequest
+	if err = json.NewDecoder(r.Body).Decode(&edit); err != nil && !errors.Is(err, io.EOF) {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	if err = edit.Validate(); err != nil {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(err)
+	}
+
+	err = h.deadLetterService.ReplayDeadLetter(r.Context(), namespaceParam(r), id, edit)
+	if err == nil {
+		return map[string]any{
+			"message":        "Dead Letter Replayed Successfully",
+			"dead_letter_id": id,
+		}, http.StatusOK, nil
+	}
+	return
+}
+
+func (h *DeadLetterHandler) Discard(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	id := chi.URLParam(r, "dead_letter_id")
+	if id == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("dead_letter_id")
+	}
+
+	err = h.deadLetterService.DiscardDeadLetter(r.Context(), namespaceParam(r), id)
+	if err == nil {
+		return map[string]any{
+			"message":        "Dead Letter Discarded Successfully",
+			"dead_letter_id": id,
+		}, http.StatusOK, nil
+	}
+	return
+}
@@ -2,34 +2,60 @@ package handlers
 
 import (
 	// Go Internal Packages
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	// Local Packages
 	errors "scheduler/errors"
 	models "scheduler/models"
+	taskio "scheduler/utils/taskio"
 
 	// External Packages
 	"github.com/go-chi/chi/v5"
 )
 
 type SchedulerService interface {
-	GetOne(ctx context.Context, taskID string) (*models.Task, error)
-	GetActive(ctx context.Context) (*models.ActiveList, error)
-	Insert(ctx context.Context, taskQP models.CreateRequest) (string, error)
-	Delete(ctx context.Context, taskID string) error
-	Enable(ctx context.Context, taskID string) error
-	Disable(ctx context.Context, taskID string) error
-	ExecuteNow(ctx context.Context, taskID string) error
+	GetOne(ctx context.Context, namespace, taskID string) (*models.Task, error)
+	GetActive(ctx context.Context, namespace string) (*models.ActiveList, error)
+	List(ctx context.Context, namespace string, selector models.Selector, limit, skip int64) (*models.TaskList, error)
+	Insert(ctx context.Context, namespace string, taskQP models.CreateRequest) (task *models.Task, replayed bool, err error)
+	InsertBatch(ctx context.Context, namespace string, mode models.BatchMode, items []models.BatchItem) ([]models.BatchItemResult, error)
+	Import(ctx context.Context, namespace string, tasks []models.Task, opts models.ImportOptions) ([]models.BatchItemResult, error)
+	Agenda(ctx context.Context, namespace string, from, to time.Time) (*models.Agenda, error)
+	Preview(ctx context.Context, namespace, taskID string, n int) (*models.Preview, error)
+	PreviewRequest(ctx context.Context, namespace string, taskQP models.CreateRequest, n int) (*models.Preview, error)
+	Update(ctx context.Context, namespace, taskID string, taskQP models.CreateRequest) error
+	Delete(ctx context.Context, namespace, taskID string) error
+	Enable(ctx context.Context, namespace, taskID string) error
+	Disable(ctx context.Context, namespace, taskID string) error
+	ExecuteNow(ctx context.Context, namespace, taskID string) error
+	ListRuns(ctx context.Context, namespace, taskID string) (*models.RunList, error)
+	PoolStats(ctx context.Context) (*models.PoolStats, error)
+	CancelRun(ctx context.Context, namespace, runID string) error
+	CancelTaskRuns(ctx context.Context, namespace, taskID string) ([]string, error)
+	Bulk(ctx context.Context, namespace string, action models.BulkAction, selector models.Selector) (*models.BulkResult, error)
 }
 
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+	maxBatchSize     = 1000
+	maxImportSize    = 10000
+)
+
 type SchedulerHandler struct {
 	schedulerService SchedulerService
+	taskRules        models.TaskRules
 }
 
-func NewSchedulerHandler(schedulerService SchedulerService) *SchedulerHandler {
-	return &SchedulerHandler{schedulerService: schedulerService}
+func NewSchedulerHandler(schedulerService SchedulerService, taskRules models.TaskRules) *SchedulerHandler {
+	return &SchedulerHandler{schedulerService: schedulerService, taskRules: taskRules}
 }
 
 func (h *SchedulerHandler) GetOne(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
@@ -38,29 +64,333 @@ func (h *SchedulerHandler) GetOne(_ http.ResponseWriter, r *http.Request) (respo
 		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
 	}
 
-	task, err := h.schedulerService.GetOne(r.Context(), taskID)
+	task, err := h.schedulerService.GetOne(r.Context(), namespaceParam(r), taskID)
 	if err == nil {
 		return task, http.StatusOK, nil
 	}
 	return
 }
 
-func (h *SchedulerHandler) Insert(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+func (h *SchedulerHandler) List(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	selector, err := models.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "invalid selector", err)
+	}
+	limit, err := intQueryParam(r, "limit", defaultListLimit)
+	if err != nil || limit <= 0 || limit > maxListLimit {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "limit must be between 1 and 1000")
+	}
+	offset, err := intQueryParam(r, "offset", 0)
+	if err != nil || offset < 0 {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "offset must be a non-negative integer")
+	}
+
+	tasks, err := h.schedulerService.List(r.Context(), namespaceParam(r), selector, limit, offset)
+	if err == nil {
+		return tasks, http.StatusOK, nil
+	}
+	return
+}
+
+// Preview returns the next fire times of a stored task.
+func (h *SchedulerHandler) Preview(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	taskID := chi.URLParam(r, "task_id")
+	if taskID == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
+	}
+	count, err := previewCount(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	preview, err := h.schedulerService.Preview(r.Context(), namespaceParam(r), taskID, count)
+	if err == nil {
+		return preview, http.StatusOK, nil
+	}
+	return
+}
+
+// PreviewRequest returns the fire times a create request would produce,
+// validating it exactly as Insert does without storing anything.
+func (h *SchedulerHandler) PreviewRequest(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	count, err := previewCount(r)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	var taskQP models.CreateRequest
+	if err = json.NewDecoder(r.Body).Decode(&taskQP); err != nil {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	taskQP.Normalize()
+	if err = taskQP.Validate(h.taskRules); err != nil {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(err)
+	}
+
+	preview, err := h.schedulerService.PreviewRequest(r.Context(), namespaceParam(r), taskQP, count)
+	if err == nil {
+		return preview, http.StatusOK, nil
+	}
+	return
+}
+
+// Insert creates a task. An Idempotency-Key header (or externalId in the body)
+// makes retries safe: replaying the same payload returns the original task.
+func (h *SchedulerHandler) Insert(w http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	return h.insert(w, r, h.taskRules)
+}
+
+// SyncInsert creates a task for sync, which may set its ownership labels.
+func (h *SchedulerHandler) SyncInsert(w http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	rules := h.taskRules
+	rules.Sync = true
+	return h.insert(w, r, rules)
+}
+
+func (h *SchedulerHandler) insert(w http.ResponseWriter, r *http.Request, rules models.TaskRules) (response any, status int, err error) {
+	var taskQP models.CreateRequest
+	if err = json.NewDecoder(r.Body).Decode(&taskQP); err != nil {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	if key := strings.TrimSpace(r.Header.Get("Idempotency-Key")); key != "" {
+		if taskQP.ExternalID != "" && taskQP.ExternalID != key {
+			return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "Idempotency-Key header does not match externalId")
+		}
+		taskQP.ExternalID = key
+	}
+	taskQP.Normalize()
+	if err = taskQP.Validate(rules); err != nil {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(err)
+	}
+
+	task, replayed, err := h.schedulerService.Insert(r.Context(), namespaceParam(r), taskQP)
+	if err != nil {
+		return
+	}
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+		return map[string]any{
+			"message": "Task Already Created",
+			"task_id": task.ID,
+			"task":    task,
+		}, http.StatusOK, nil
+	}
+	return map[string]any{
+		"message": "Task Created Successfully",
+		"task_id": task.ID,
+	}, http.StatusCreated, nil
+}
+
+// InsertBatch validates every task up front. In atomic mode a single invalid
+// task rejects the whole batch; in best-effort mode only the valid ones are created.
+func (h *SchedulerHandler) InsertBatch(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	var req models.BatchCreateRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	if req.Mode == "" {
+		req.Mode = models.BatchAtomic
+	}
+	if req.Mode != models.BatchAtomic && req.Mode != models.BatchBestEffort {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "mode must be atomic or best_effort")
+	}
+	if len(req.Tasks) == 0 || len(req.Tasks) > maxBatchSize {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "tasks must contain between 1 and 1000 items")
+	}
+
+	results := make([]models.BatchItemResult, len(req.Tasks))
+	items := make([]models.BatchItem, 0, len(req.Tasks))
+	all := errors.ValidationErrs()
+	for i := range req.Tasks {
+		results[i].Index = i
+		req.Tasks[i].Normalize()
+		vErr := req.Tasks[i].Validate(h.taskRules)
+		if vErr == nil {
+			items = append(items, models.BatchItem{Index: i, Request: req.Tasks[i]})
+			continue
+		}
+		var ve errors.ValidationErrors
+		if !errors.As(vErr, &ve) {
+			ve = errors.ValidationErrors{{Field: "", Error: vErr.Error()}}
+		}
+		results[i].ValidationErrors = ve
+		for _, fe := range ve {
+			all.Add(fmt.Sprintf("tasks[%d].%s", i, fe.Field), fe.Error)
+		}
+	}
+	if req.Mode == models.BatchAtomic && all.Len() > 0 {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(all.Err())
+	}
+
+	if len(items) > 0 {
+		created, err := h.schedulerService.InsertBatch(r.Context(), namespaceParam(r), req.Mode, items)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, c := range created {
+			results[c.Index] = c
+		}
+	}
+	return batchResponse(req.Mode, results)
+}
+
+// batchResponse summarises per-item results, answering 201 only when every item was created.
+func batchResponse(mode models.BatchMode, results []models.BatchItemResult) (any, int, error) {
+	res := &models.BatchResult{Mode: mode, Results: results}
+	for _, item := range results {
+		if item.TaskID != "" {
+			res.Created++
+		} else {
+			res.Failed++
+		}
+	}
+	if res.Failed == 0 {
+		return res, http.StatusCreated, nil
+	}
+	return res, http.StatusOK, nil
+}
+
+// Export writes every task matching the optional selector as JSON lines
+// (default) or YAML documents, suitable for Import.
+func (h *SchedulerHandler) Export(w http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	selector, err := models.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "invalid selector", err)
+	}
+	format, err := formatParam(r, models.FormatJSONL)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	tasks, err := h.schedulerService.List(r.Context(), namespaceParam(r), selector, 0, 0)
+	if err != nil {
+		return
+	}
+	// Encode before writing the header so that a failure is still reported as an error.
+	var buf bytes.Buffer
+	if err = taskio.Encode(&buf, format, tasks.Tasks); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to export tasks: %w", err)
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+	return nil, 0, nil
+}
+
+// Import stores tasks produced by Export. Tasks are imported disabled with new
+// IDs unless enable / preserve_ids are set, and each rewrite_host=from=to rule
+// replaces a target url host. Every task is validated before anything is written.
+func (h *SchedulerHandler) Import(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	q := r.URL.Query()
+	opts := models.ImportOptions{
+		Mode:         models.BatchMode(q.Get("mode")),
+		HostRewrites: make(map[string]string),
+	}
+	if opts.Mode == "" {
+		opts.Mode = models.BatchAtomic
+	}
+	if opts.Mode != models.BatchAtomic && opts.Mode != models.BatchBestEffort {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "mode must be atomic or best_effort")
+	}
+	if opts.PreserveIDs, err = boolQueryParam(r, "preserve_ids"); err != nil {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "preserve_ids must be a boolean")
+	}
+	if opts.Enable, err = boolQueryParam(r, "enable"); err != nil {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "enable must be a boolean")
+	}
+	for _, rule := range q["rewrite_host"] {
+		from, to, err := models.ParseHostRewrite(rule)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, err.Error())
+		}
+		opts.HostRewrites[from] = to
+	}
+	format, err := formatParam(r, formatFromContentType(r.Header.Get("Content-Type")))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	tasks, err := taskio.Decode(r.Body, format, maxImportSize)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
+	}
+	if len(tasks) == 0 {
+		return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "no tasks to import")
+	}
+
+	results := make([]models.BatchItemResult, len(tasks))
+	valid := make([]models.Task, 0, len(tasks))
+	validIdx := make([]int, 0, len(tasks)) // position in tasks of each entry in valid
+	all := errors.ValidationErrs()
+	for i := range tasks {
+		results[i].Index = i
+		tasks[i].PrepareImport(opts)
+		vErr := tasks[i].ValidateImport(h.taskRules, opts.PreserveIDs)
+		if vErr == nil {
+			valid = append(valid, tasks[i])
+			validIdx = append(validIdx, i)
+			continue
+		}
+		var ve errors.ValidationErrors
+		if !errors.As(vErr, &ve) {
+			ve = errors.ValidationErrors{{Field: "", Error: vErr.Error()}}
+		}
+		results[i].ValidationErrors = ve
+		for _, fe := range ve {
+			all.Add(fmt.Sprintf("tasks[%d].%s", i, fe.Field), fe.Error)
+		}
+	}
+	if opts.Mode == models.BatchAtomic && all.Len() > 0 {
+		return nil, http.StatusBadRequest, errors.ValidationFailedErr(all.Err())
+	}
+
+	if len(valid) > 0 {
+		imported, err := h.schedulerService.Import(r.Context(), namespaceParam(r), valid, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, res := range imported {
+			res.Index = validIdx[res.Index]
+			results[res.Index] = res
+		}
+	}
+	return batchResponse(opts.Mode, results)
+}
+
+// Update replaces a task's definition. The externalId of a task cannot be changed.
+func (h *SchedulerHandler) Update(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	return h.update(r, h.taskRules)
+}
+
+// SyncUpdate replaces a task's definition for sync, which may set its
+// ownership labels.
+func (h *SchedulerHandler) SyncUpdate(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	rules := h.taskRules
+	rules.Sync = true
+	return h.update(r, rules)
+}
+
+func (h *SchedulerHandler) update(r *http.Request, rules models.TaskRules) (response any, status int, err error) {
+	taskID := chi.URLParam(r, "task_id")
+	if taskID == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
+	}
+
 	var taskQP models.CreateRequest
 	if err = json.NewDecoder(r.Body).Decode(&taskQP); err != nil {
 		return nil, http.StatusBadRequest, errors.InvalidBodyErr(err)
 	}
+	taskQP.ExternalID = ""
 	taskQP.Normalize()
-	if err = taskQP.Validate(); err != nil {
+	if err = taskQP.ValidateUpdate(rules); err != nil {
 		return nil, http.StatusBadRequest, errors.ValidationFailedErr(err)
 	}
 
-	taskID, err := h.schedulerService.Insert(r.Context(), taskQP)
+	err = h.schedulerService.Update(r.Context(), namespaceParam(r), taskID, taskQP)
 	if err == nil {
 		return map[string]any{
-			"message": "Task Created Successfully",
+			"message": "Task Updated Successfully",
 			"task_id": taskID,
-		}, http.StatusCreated, nil
+		}, http.StatusOK, nil
 	}
 	return
 }
@@ -71,7 +401,7 @@ func (h *SchedulerHandler) Delete(_ http.ResponseWriter, r *http.Request) (respo
 		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
 	}
 
-	err = h.schedulerService.Delete(r.Context(), taskID)
+	err = h.schedulerService.Delete(r.Context(), namespaceParam(r), taskID)
 	if err == nil {
 		return map[string]any{
 			"message": "Task Deleted Successfully",
@@ -87,7 +417,7 @@ func (h *SchedulerHandler) Enable(_ http.ResponseWriter, r *http.Request) (respo
 		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
 	}
 
-	err = h.schedulerService.Enable(r.Context(), taskID)
+	err = h.schedulerService.Enable(r.Context(), namespaceParam(r), taskID)
 	if err == nil {
 		return map[string]any{
 			"message": "Task Enabled Successfully",
@@ -103,7 +433,7 @@ func (h *SchedulerHandler) Disable(_ http.ResponseWriter, r *http.Request) (resp
 		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
 	}
 
-	err = h.schedulerService.Disable(r.Context(), taskID)
+	err = h.schedulerService.Disable(r.Context(), namespaceParam(r), taskID)
 	if err == nil {
 		return map[string]any{
 			"message": "Task Disabled Successfully",
@@ -114,7 +444,7 @@ func (h *SchedulerHandler) Disable(_ http.ResponseWriter, r *http.Request) (resp
 }
 
 func (h *SchedulerHandler) GetActive(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
-	activeTasks, err := h.schedulerService.GetActive(r.Context())
+	activeTasks, err := h.schedulerService.GetActive(r.Context(), namespaceParam(r))
 	if err == nil {
 		return activeTasks, http.StatusOK, nil
 	}
@@ -127,7 +457,7 @@ func (h *SchedulerHandler) Execute(_ http.ResponseWriter, r *http.Request) (resp
 		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
 	}
 
-	err = h.schedulerService.ExecuteNow(r.Context(), taskID)
+	err = h.schedulerService.ExecuteNow(r.Context(), namespaceParam(r), taskID)
 	if err == nil {
 		return map[string]any{
 			"message": "Task Executed Successfully",
@@ -136,3 +466,164 @@ func (h *SchedulerHandler) Execute(_ http.ResponseWriter, r *http.Request) (resp
 	}
 	return
 }
+
+// ListRuns lists the executions in flight on this instance, filtered by ?task_id= when given.
+func (h *SchedulerHandler) ListRuns(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	runs, err := h.schedulerService.ListRuns(r.Context(), namespaceParam(r), r.URL.Query().Get("task_id"))
+	if err == nil {
+		return runs, http.StatusOK, nil
+	}
+	return
+}
+
+// PoolStats reports running and queued executions of this instance's execution pool.
+func (h *SchedulerHandler) PoolStats(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	stats, err := h.schedulerService.PoolStats(r.Context())
+	if err == nil {
+		return stats, http.StatusOK, nil
+	}
+	return
+}
+
+// CancelRun cancels one in-flight execution on this instance.
+func (h *SchedulerHandler) CancelRun(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	runID := chi.URLParam(r, "run_id")
+	if runID == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("run_id")
+	}
+
+	err = h.schedulerService.CancelRun(r.Context(), namespaceParam(r), runID)
+	if err == nil {
+		return map[string]any{
+			"message": "Execution Cancelled Successfully",
+			"run_id":  runID,
+		}, http.StatusOK, nil
+	}
+	return
+}
+
+// CancelTaskRuns cancels every in-flight execution of a task on this instance.
+func (h *SchedulerHandler) CancelTaskRuns(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+	taskID := chi.URLParam(r, "task_id")
+	if taskID == "" {
+		return nil, http.StatusBadRequest, errors.EmptyParamErr("task_id")
+	}
+
+	runIDs, err := h.schedulerService.CancelTaskRuns(r.Context(), namespaceParam(r), taskID)
+	if err == nil {
+		return map[string]any{
+			"message":        "Executions Cancelled Successfully",
+			"task_id":        taskID,
+			"cancelled_runs": runIDs,
+		}, http.StatusOK, nil
+	}
+	return
+}
+
+// Bulk returns a handler applying action to every task matching the
+// required "selector" query parameter.
+func (h *SchedulerHandler) Bulk(action models.BulkAction) func(http.ResponseWriter, *http.Request) (any, int, error) {
+	return func(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+		selector, err := models.ParseSelector(r.URL.Query().Get("selector"))
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "invalid selector", err)
+		}
+		if len(selector) == 0 {
+			return nil, http.StatusBadRequest, errors.EmptyParamErr("selector")
+		}
+
+		res, err := h.schedulerService.Bulk(r.Context(), namespaceParam(r), action, selector)
+		if err == nil {
+			return res, http.StatusOK, nil
+		}
+		return
+	}
+}
+
+// Agenda returns the fires expected between ?from= and ?to= (RFC3339, default
+// the next 24 hours) grouped by hour. With allNamespaces it covers every namespace.
+func (h *SchedulerHandler) Agenda(allNamespaces bool) func(http.ResponseWriter, *http.Request) (any, int, error) {
+	return func(_ http.ResponseWriter, r *http.Request) (response any, status int, err error) {
+		from, err := timeQueryParam(r, "from", time.Now().UTC())
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "from must be an RFC3339 time")
+		}
+		to, err := timeQueryParam(r, "to", from.Add(models.DefaultAgendaWindow))
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "to must be an RFC3339 time")
+		}
+		if !to.After(from) || to.Sub(from) > models.MaxAgendaWindow {
+			return nil, http.StatusBadRequest, errors.NewError(errors.Invalid, "to must be after from and within 7 days of it")
+		}
+
+		namespace := ""
+		if !allNamespaces {
+			namespace = namespaceParam(r)
+		}
+		agenda, err := h.schedulerService.Agenda(r.Context(), namespace, from, to)
+		if err == nil {
+			return agenda, http.StatusOK, nil
+		}
+		return
+	}
+}
+
+// intQueryParam parses an integer query parameter, returning def when it is absent.
+func intQueryParam(r *http.Request, name string, def int64) (int64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+func previewCount(r *http.Request) (int, error) {
+	count, err := intQueryParam(r, "count", models.DefaultPreviewCount)
+	if err != nil || count <= 0 || count > models.MaxPreviewCount {
+		return 0, errors.NewError(errors.Invalid, fmt.Sprintf("count must be between 1 and %d", models.MaxPreviewCount))
+	}
+	return int(count), nil
+}
+
+// timeQueryParam parses an RFC3339 query parameter, returning def when it is absent.
+func timeQueryParam(r *http.Request, name string, def time.Time) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// boolQueryParam parses a boolean query parameter, returning false when it is absent.
+func boolQueryParam(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+// formatParam returns the transfer format from the format query parameter, or def.
+func formatParam(r *http.Request, def models.TransferFormat) (models.TransferFormat, error) {
+	format := models.TransferFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		return def, nil
+	}
+	if err := format.Validate(); err != nil {
+		return "", errors.NewError(errors.Invalid, err.Error())
+	}
+	return format, nil
+}
+
+func formatFromContentType(contentType string) models.TransferFormat {
+	if strings.Contains(contentType, "yaml") {
+		return models.FormatYAML
+	}
+	return models.FormatJSONL
+}
+
+// namespaceParam returns the namespace from the route, or the default
+// namespace for the un-namespaced routes.
+func namespaceParam(r *http.Request) string {
+	return models.NamespaceOrDefault(chi.URLParam(r, "namespace"))
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	// Go Internal Packages
+	"context"
+	"net/http"
+	"strings"
+
+	// Local Packages
+	errors "scheduler/errors"
+	response "scheduler/http/response"
+	models "scheduler/models"
+	auth "scheduler/services/auth"
+
+	// External Packages
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Authenticator is satisfied by any service that can resolve an API key or a
+// bearer token to a principal.
+type Authenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*models.Principal, error)
+	AuthenticateToken(ctx context.Context, token string) (*models.Principal, error)
+}
+
+// Authenticate resolves the caller from an "Authorization: Bearer <jwt>" header,
+// or otherwise from the X-API-Key header (or "Authorization: ApiKey <key>"),
+// and stores the principal in the request context.
+func Authenticate(logger *zap.Logger, authenticator Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var principal *models.Principal
+			var err error
+			if token, ok := credentialFromHeader(r, "Bearer"); ok {
+				principal, err = authenticator.AuthenticateToken(r.Context(), token)
+			} else {
+				principal, err = authenticator.Authenticate(r.Context(), apiKeyFromRequest(r))
+			}
+			if err != nil {
+				respondAuthError(w, logger, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireScope rejects requests whose principal does not hold scope.
+// It must run after Authenticate.
+func RequireScope(scope models.Scope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				response.RespondMessage(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			if !principal.HasScope(scope) {
+				response.RespondMessage(w, http.StatusForbidden, "missing required scope: "+string(scope))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireNamespace rejects requests for a namespace the principal is not
+// restricted to. Un-namespaced routes act on the default namespace.
+// It must run after Authenticate.
+func RequireNamespace() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			namespace := models.NamespaceOrDefault(chi.URLParam(r, "namespace"))
+			if !models.ValidNamespace(namespace) {
+				response.RespondMessage(w, http.StatusBadRequest, "invalid namespace")
+				return
+			}
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				response.RespondMessage(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			if !principal.CanAccessNamespace(namespace) {
+				response.RespondMessage(w, http.StatusForbidden, "no access to namespace: "+namespace)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireGlobal rejects principals restricted to specific namespaces.
+// It must run after Authenticate.
+func RequireGlobal() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				response.RespondMessage(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			if !principal.IsGlobal() {
+				response.RespondMessage(w, http.StatusForbidden, "namespace-restricted credentials cannot use this route")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	key, _ := credentialFromHeader(r, "ApiKey")
+	return key
+}
+
+// credentialFromHeader returns the credential of an Authorization header using the given scheme.
+func credentialFromHeader(r *http.Request, scheme string) (string, bool) {
+	got, credential, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(got, scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(credential), true
+}
+
+func respondAuthError(w http.ResponseWriter, logger *zap.Logger, err error) {
+	var typedErr *errors.Error
+	if errors.As(err, &typedErr) {
+		response.RespondError(w, typedErr)
+		return
+	}
+	logger.Error("Authentication Failed", zap.Error(err))
+	response.RespondMessage(w, http.StatusInternalServerError, "internal error")
+}
@@ -9,8 +9,9 @@ import (
 	// Local Packages
 	errors "scheduler/errors"
 	handlers "scheduler/http/handlers"
-	reqlog "scheduler/http/middleware"
+	httpmw "scheduler/http/middleware"
 	response "scheduler/http/response"
+	models "scheduler/models"
 	version "scheduler/utils/version"
 
 	// External Packages
@@ -25,52 +26,73 @@ type HealthChecker interface {
 }
 
 type Server struct {
-	close     func()
-	health    HealthChecker
-	logger    *zap.Logger
-	prefix    string
-	scheduler *handlers.SchedulerHandler
+	authn       httpmw.Authenticator
+	apiKeys     *handlers.APIKeyHandler
+	callbacks   *handlers.CallbackHandler
+	close       func()
+	deadLetters *handlers.DeadLetterHandler
+	health      HealthChecker
+	logger      *zap.Logger
+	prefix      string
+	scheduler   *handlers.SchedulerHandler
 }
 
 func NewServer(
 	logger *zap.Logger,
 	prefix string,
 	health HealthChecker,
+	authn httpmw.Authenticator,
 	scheduler *handlers.SchedulerHandler,
+	apiKeys *handlers.APIKeyHandler,
+	deadLetters *handlers.DeadLetterHandler,
+	callbacks *handlers.CallbackHandler,
 	close func(),
 ) *Server {
 	return &Server{
-		close:     close,
-		health:    health,
-		logger:    logger,
-		prefix:    prefix,
-		scheduler: scheduler,
+		authn:       authn,
+		apiKeys:     apiKeys,
+		callbacks:   callbacks,
+		close:       close,
+		deadLetters: deadLetters,
+		health:      health,
+		logger:      logger,
+		prefix:      prefix,
+		scheduler:   scheduler,
 	}
 }
 
 func (s *Server) Listen(ctx context.Context, addr string) error {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(reqlog.RequestLogger(s.logger))
+	r.Use(httpmw.RequestLogger(s.logger))
 	r.Use(middleware.Recoverer)
 
 	r.Route(s.prefix, func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			r.Get("/health", s.HealthCheckHandler)
 			r.Get("/build", s.BuildInfoHandler)
+			// Targets call back with the one-time token of the run, not an API key.
+			r.Post("/callbacks/{callback_id}", s.ToHTTPHandlerFunc(s.callbacks.Complete))
 
 			r.Group(func(r chi.Router) {
-				r.Route("/task", func(r chi.Router) {
-					r.Get("/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.GetOne))
-					r.Post("/", s.ToHTTPHandlerFunc(s.scheduler.Insert))
-					r.Patch("/{task_id}/enable", s.ToHTTPHandlerFunc(s.scheduler.Enable))
-					r.Patch("/{task_id}/disable", s.ToHTTPHandlerFunc(s.scheduler.Disable))
-					r.Delete("/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.Delete))
-				})
+				r.Use(httpmw.Authenticate(s.logger, s.authn))
+
+				// Un-namespaced routes act on the default namespace.
+				r.Group(s.taskRoutes)
+				r.Route("/namespaces/{namespace}", s.taskRoutes)
 
-				r.Route("/helpers", func(r chi.Router) {
-					r.Get("/active-tasks", s.ToHTTPHandlerFunc(s.scheduler.GetActive))
-					r.Post("/execute-task/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.Execute))
+				// Scheduler-wide views across every namespace.
+				r.With(s.require(models.ScopeRead), httpmw.RequireGlobal()).
+					Get("/agenda", s.ToHTTPHandlerFunc(s.scheduler.Agenda(true)))
+				r.With(s.require(models.ScopeRead), httpmw.RequireGlobal()).
+					Get("/executions/pool", s.ToHTTPHandlerFunc(s.scheduler.PoolStats))
+
+				r.Route("/keys", func(r chi.Router) {
+					r.Use(s.require(models.ScopeAdmin))
+					r.Use(httpmw.RequireGlobal())
+					r.Get("/", s.ToHTTPHandlerFunc(s.apiKeys.List))
+					r.Post("/", s.ToHTTPHandlerFunc(s.apiKeys.Create))
+					r.Delete("/{key_id}", s.ToHTTPHandlerFunc(s.apiKeys.Revoke))
 				})
 			})
 		})
@@ -122,6 +144,57 @@ func (s *Server) ToHTTPHandlerFunc(handler func(w http.ResponseWriter, r *http.R
 	}
 }
 
+// taskRoutes registers the task and helper routes of a single namespace.
+func (s *Server) taskRoutes(r chi.Router) {
+	r.Use(httpmw.RequireNamespace())
+
+	r.Route("/task", func(r chi.Router) {
+		r.With(s.require(models.ScopeRead)).Get("/", s.ToHTTPHandlerFunc(s.scheduler.List))
+		r.With(s.require(models.ScopeRead)).Get("/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.GetOne))
+		r.With(s.require(models.ScopeWrite)).Post("/", s.ToHTTPHandlerFunc(s.scheduler.Insert))
+		r.With(s.require(models.ScopeWrite)).Post("/batch", s.ToHTTPHandlerFunc(s.scheduler.InsertBatch))
+		r.With(s.require(models.ScopeWrite)).Post("/sync", s.ToHTTPHandlerFunc(s.scheduler.SyncInsert))
+		r.With(s.require(models.ScopeRead)).Post("/preview", s.ToHTTPHandlerFunc(s.scheduler.PreviewRequest))
+		r.With(s.require(models.ScopeRead)).Get("/{task_id}/preview", s.ToHTTPHandlerFunc(s.scheduler.Preview))
+		r.With(s.require(models.ScopeRead)).Get("/export", s.ToHTTPHandlerFunc(s.scheduler.Export))
+		r.With(s.require(models.ScopeWrite)).Post("/import", s.ToHTTPHandlerFunc(s.scheduler.Import))
+		r.With(s.require(models.ScopeWrite)).Post("/bulk/enable", s.ToHTTPHandlerFunc(s.scheduler.Bulk(models.BulkEnable)))
+		r.With(s.require(models.ScopeWrite)).Post("/bulk/disable", s.ToHTTPHandlerFunc(s.scheduler.Bulk(models.BulkDisable)))
+		r.With(s.require(models.ScopeWrite)).Post("/bulk/delete", s.ToHTTPHandlerFunc(s.scheduler.Bulk(models.BulkDelete)))
+		r.With(s.require(models.ScopeExecute)).Post("/bulk/execute", s.ToHTTPHandlerFunc(s.scheduler.Bulk(models.BulkExecute)))
+		r.With(s.require(models.ScopeWrite)).Put("/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.Update))
+		r.With(s.require(models.ScopeWrite)).Put("/{task_id}/sync", s.ToHTTPHandlerFunc(s.scheduler.SyncUpdate))
+		r.With(s.require(models.ScopeExecute)).Post("/{task_id}/cancel", s.ToHTTPHandlerFunc(s.scheduler.CancelTaskRuns))
+		r.With(s.require(models.ScopeWrite)).Patch("/{task_id}/enable", s.ToHTTPHandlerFunc(s.scheduler.Enable))
+		r.With(s.require(models.ScopeWrite)).Patch("/{task_id}/disable", s.ToHTTPHandlerFunc(s.scheduler.Disable))
+		r.With(s.require(models.ScopeWrite)).Delete("/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.Delete))
+	})
+
+	r.Route("/runs", func(r chi.Router) {
+		r.With(s.require(models.ScopeRead)).Get("/", s.ToHTTPHandlerFunc(s.scheduler.ListRuns))
+		r.With(s.require(models.ScopeRead)).Get("/waiting", s.ToHTTPHandlerFunc(s.callbacks.ListWaiting))
+		r.With(s.require(models.ScopeExecute)).Post("/{run_id}/cancel", s.ToHTTPHandlerFunc(s.scheduler.CancelRun))
+	})
+
+	r.Route("/dead-letters", func(r chi.Router) {
+		r.With(s.require(models.ScopeRead)).Get("/", s.ToHTTPHandlerFunc(s.deadLetters.List))
+		r.With(s.require(models.ScopeRead)).Get("/{dead_letter_id}", s.ToHTTPHandlerFunc(s.deadLetters.GetOne))
+		r.With(s.require(models.ScopeExecute)).Post("/{dead_letter_id}/replay", s.ToHTTPHandlerFunc(s.deadLetters.Replay))
+		r.With(s.require(models.ScopeWrite)).Delete("/{dead_letter_id}", s.ToHTTPHandlerFunc(s.deadLetters.Discard))
+	})
+
+	r.Route("/helpers", func(r chi.Router) {
+		r.With(s.require(models.ScopeRead)).Get("/active-tasks", s.ToHTTPHandlerFunc(s.scheduler.GetActive))
+		r.With(s.require(models.ScopeRead)).Get("/agenda", s.ToHTTPHandlerFunc(s.scheduler.Agenda(false)))
+		r.With(s.require(models.ScopeExecute)).Post("/execute-task/{task_id}", s.ToHTTPHandlerFunc(s.scheduler.Execute))
+	})
+}
+
+// require returns middleware that rejects callers without the given scope.
+func (s *Server) require(scope models.Scope) func(http.Handler) http.Handler {
+	return httpmw.RequireScope(scope)
+}
+
 // BuildInfoHandler returns the build version and timestamp stamped at compile time.
 func (s *Server) BuildInfoHandler(w http.ResponseWriter, r *http.Request) {
 	response.RespondJSON(w, http.StatusOK, version.Get())
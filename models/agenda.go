@@ -0,0 +1,90 @@
+package models
+
+import (
+	// Go Internal Packages
+	"sort"
+	"time"
+
+	// Local Packages
+	helpers "scheduler/utils/helpers"
+)
+
+const (
+	DefaultAgendaWindow = 24 * time.Hour
+	MaxAgendaWindow     = 7 * 24 * time.Hour
+)
+
+// AgendaHour counts the fires expected in one UTC hour.
+type AgendaHour struct {
+	Hour            string         `json:"hour"` // start of the hour, UTC
+	Total           int            `json:"total"`
+	PeakMinute      string         `json:"peakMinute"` // busiest minute of the hour, UTC
+	PeakMinuteFires int            `json:"peakMinuteFires"`
+	ByTaskType      map[string]int `json:"byTaskType"`
+	ByHost          map[string]int `json:"byHost"`
+}
+
+type Agenda struct {
+	Namespace string       `json:"namespace,omitempty"` // empty for every namespace
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Total     int          `json:"total"`
+	Tasks     int          `json:"tasks"` // tasks with at least one fire in the window
+	Hours     []AgendaHour `json:"hours"`
+}
+
+// BuildAgenda groups the fires of tasks in [from, to) by hour. Hours without
+// fires are omitted.
+func BuildAgenda(tasks []Task, namespace string, curUnix, from, to helpers.Unix) *Agenda {
+	agenda := &Agenda{
+		Namespace: namespace,
+		From:      from.Time().Format(time.RFC3339),
+		To:        to.Time().Format(time.RFC3339),
+		Hours:     []AgendaHour{},
+	}
+
+	hours := make(map[helpers.Unix]*AgendaHour)
+	minutes := make(map[helpers.Unix]int)
+	for i := range tasks {
+		t := &tasks[i]
+		if !t.Enable {
+			continue
+		}
+		fires := t.FireTimesBetween(curUnix, from, to)
+		if len(fires) == 0 {
+			continue
+		}
+		agenda.Tasks++
+		host := t.TaskData.TargetHost()
+		for _, at := range fires {
+			hourStart := at - at%3600
+			h, ok := hours[hourStart]
+			if !ok {
+				h = &AgendaHour{
+					Hour:       hourStart.Time().Format(time.RFC3339),
+					ByTaskType: make(map[string]int),
+					ByHost:     make(map[string]int),
+				}
+				hours[hourStart] = h
+			}
+			h.Total++
+			h.ByTaskType[t.TaskData.TaskType]++
+			h.ByHost[host]++
+			minutes[at-at%60]++
+			agenda.Total++
+		}
+	}
+
+	for minute, n := range minutes {
+		h := hours[minute-minute%3600]
+		if n > h.PeakMinuteFires || (n == h.PeakMinuteFires && minute.Time().Format(time.RFC3339) < h.PeakMinute) {
+			h.PeakMinute = minute.Time().Format(time.RFC3339)
+			h.PeakMinuteFires = n
+		}
+	}
+	for _, h := range hours {
+		agenda.Hours = append(agenda.Hours, *h)
+	}
+	sort.Slice(agenda.Hours, func(i, j int) bool { return agenda.Hours[i].Hour < agenda.Hours[j].Hour })
+	return agenda
+}
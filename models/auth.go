@@ -0,0 +1,97 @@
+package models
+
+import (
+	// Go Internal Packages
+	"fmt"
+	"slices"
+
+	// Local Packages
+	errors "scheduler/errors"
+	helpers "scheduler/utils/helpers"
+)
+
+type Scope string
+
+const (
+	ScopeRead    Scope = "read"
+	ScopeWrite   Scope = "write"
+	ScopeExecute Scope = "execute"
+	ScopeAdmin   Scope = "admin"
+)
+
+func (s Scope) Validate() error {
+	switch s {
+	case ScopeRead, ScopeWrite, ScopeExecute, ScopeAdmin:
+		return nil
+	default:
+		return fmt.Errorf("invalid scope: %s", string(s))
+	}
+}
+
+type APIKey struct {
+	ID         string   `json:"_id" bson:"_id"`
+	Name       string   `json:"name" bson:"name"`
+	Prefix     string   `json:"prefix" bson:"prefix"`
+	KeyHash    string   `json:"-" bson:"keyHash"`
+	Scopes     []Scope  `json:"scopes" bson:"scopes"`
+	Namespaces []string `json:"namespaces" bson:"namespaces"` // empty means all namespaces
+	Revoked    bool     `json:"revoked" bson:"revoked"`
+	CreatedBy  string   `json:"createdBy" bson:"createdBy"`
+	CreatedAt  string   `json:"createdAt" bson:"createdAt"`   // UTC
+	LastUsedAt string   `json:"lastUsedAt" bson:"lastUsedAt"` // UTC
+}
+
+type CreateAPIKeyRequest struct {
+	Name       string   `json:"name"`
+	Scopes     []Scope  `json:"scopes"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// CreatedAPIKey is returned once on creation; the raw key is never stored.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject    string   `json:"subject"`
+	Kind       string   `json:"kind"`
+	Roles      []string `json:"roles,omitempty"`
+	Scopes     []Scope  `json:"scopes"`
+	Namespaces []string `json:"namespaces,omitempty"` // empty means all namespaces
+}
+
+// HasScope reports whether the principal holds scope. The admin scope grants every scope.
+func (p *Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, ScopeAdmin) || slices.Contains(p.Scopes, scope)
+}
+
+// IsGlobal reports whether the principal is not restricted to any namespaces.
+func (p *Principal) IsGlobal() bool {
+	return len(p.Namespaces) == 0
+}
+
+// CanAccessNamespace reports whether the principal may act on tasks in ns.
+func (p *Principal) CanAccessNamespace(ns string) bool {
+	return p.IsGlobal() || slices.Contains(p.Namespaces, ns)
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	ve := errors.ValidationErrs()
+
+	helpers.ValidateRequiredString(ve, "name", r.Name)
+	helpers.ValidateRequiredSlice(ve, "scopes", r.Scopes)
+	for i, scope := range r.Scopes {
+		if err := scope.Validate(); err != nil {
+			ve.Add(fmt.Sprintf("scopes[%d]", i), err.Error())
+		}
+	}
+	for i, ns := range r.Namespaces {
+		if !ValidNamespace(ns) {
+			ve.Add(fmt.Sprintf("namespaces[%d]", i), "must be a lowercase DNS label")
+		}
+	}
+
+	return ve.Err()
+}
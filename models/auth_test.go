@@ -0,0 +1,22 @@
+package models
+
+import (
+	// Go Internal Packages
+	"testing"
+
+	// Local Packages
+	config "scheduler/config"
+)
+
+// config validates role_scopes against its own list of scopes.
+func TestConfigScopesMatchScopes(t *testing.T) {
+	scopes := []Scope{ScopeRead, ScopeWrite, ScopeExecute, ScopeAdmin}
+	if len(config.Scopes) != len(scopes) {
+		t.Fatalf("config.Scopes = %v, want %v", config.Scopes, scopes)
+	}
+	for _, s := range config.Scopes {
+		if err := Scope(s).Validate(); err != nil {
+			t.Errorf("config scope %q: %v", s, err)
+		}
+	}
+}
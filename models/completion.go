@@ -0,0 +1,286 @@
+package models
+
+import (
+	// Go Internal Packages
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	// Local Packages
+	errors "scheduler/errors"
+	helpers "scheduler/utils/helpers"
+	secrets "scheduler/utils/secrets"
+)
+
+// CompletionMode is how the scheduler learns that a run has finished.
+type CompletionMode string
+
+const (
+	// CompletionSync treats a 2xx response as success.
+	CompletionSync CompletionMode = "sync"
+	// CompletionCallback treats a 2xx response as accepted and waits for the
+	// target to report the result to a one-time callback URL.
+	CompletionCallback CompletionMode = "callback"
+	// CompletionPoll treats a 2xx response as a job handle and polls the job's
+	// status URL until it reports a terminal state.
+	CompletionPoll CompletionMode = "poll"
+)
+
+const (
+	DefaultCompletionTimeout = 3600      // seconds
+	MaxCompletionTimeout     = 7 * 86400 // seconds
+	DefaultPollInterval      = 30        // seconds
+	MaxPollInterval          = 3600      // seconds
+)
+
+// Headers that pass the callback URL and token of a run to its target. The
+// target returns the token in CallbackTokenHeader when it calls back.
+const (
+	CallbackURLHeader   = "X-Scheduler-Callback-URL"
+	CallbackTokenHeader = "X-Scheduler-Callback-Token"
+)
+
+// Completion configures asynchronous completion of a task's runs.
+type Completion struct {
+	Mode CompletionMode `json:"mode" bson:"mode"`
+	// TimeoutSeconds is how long a run may wait for its result, counted from
+	// its first attempt. The run fails once it has passed.
+	TimeoutSeconds int `json:"timeoutSeconds" bson:"timeoutSeconds"`
+	// Poll is required in poll mode and not allowed otherwise.
+	Poll *Poll `json:"poll,omitempty" bson:"poll,omitempty"`
+}
+
+// Poll configures how the status of an accepted job is polled.
+type Poll struct {
+	// StatusURL is the URL to poll. It may reference fields of the JSON
+	// response that accepted the job as {{path}}, e.g.
+	// "https://api.example.com/jobs/{{job.id}}", in its path, query or
+	// fragment only, and may be relative to the task's url. When empty, the
+	// response's Location header is used.
+	StatusURL       string        `json:"statusUrl,omitempty" bson:"statusUrl,omitempty"`
+	IntervalSeconds int           `json:"intervalSeconds" bson:"intervalSeconds"`
+	Success         PollCondition `json:"success" bson:"success"`
+	Failure         PollCondition `json:"failure" bson:"failure"`
+}
+
+// PollCondition matches a JSON status response whose field at Path has one of
+// Values. Numbers and booleans are compared in their JSON text form.
+type PollCondition struct {
+	Path   string   `json:"path" bson:"path"` // dot-separated, array elements by index
+	Values []string `json:"values" bson:"values"`
+}
+
+// placeholderPattern matches {{path}} references in a status URL. Secret
+// references contain a space and never match.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// Is reports whether runs complete in the given mode. A nil completion is sync.
+func (c *Completion) Is(mode CompletionMode) bool {
+	if c == nil || c.Mode == "" {
+		return mode == CompletionSync
+	}
+	return c.Mode == mode
+}
+
+// IsAsync reports whether runs wait for their result after the request.
+func (c *Completion) IsAsync() bool {
+	return c != nil && c.Mode != "" && c.Mode != CompletionSync
+}
+
+func (c *Completion) normalize() {
+	if c.IsAsync() && c.TimeoutSeconds == 0 {
+		c.TimeoutSeconds = DefaultCompletionTimeout
+	}
+	if c != nil && c.Poll != nil && c.Poll.IntervalSeconds == 0 {
+		c.Poll.IntervalSeconds = DefaultPollInterval
+	}
+}
+
+// validate checks the completion settings. Callback mode needs callbacks to be
+// configured, since the target could never be told where to report.
+func (c *Completion) validate(ve *errors.ValidationErrorBuilder, field string, callbacks bool) {
+	if c == nil {
+		return
+	}
+	switch c.Mode {
+	case "", CompletionSync, CompletionCallback, CompletionPoll:
+	default:
+		ve.Add(field+".mode", "must be one of sync, callback, poll")
+	}
+	if c.Mode == CompletionCallback && !callbacks {
+		ve.Add(field+".mode", "callback mode requires callbacks.base_url to be configured")
+	}
+	if c.TimeoutSeconds < 0 || c.TimeoutSeconds > MaxCompletionTimeout {
+		ve.Add(field+".timeoutSeconds", fmt.Sprintf("must be between 0 and %d", MaxCompletionTimeout))
+	}
+	switch {
+	case c.Mode == CompletionPoll && c.Poll == nil:
+		ve.Add(field+".poll", "is required in poll mode")
+	case c.Mode != CompletionPoll && c.Poll != nil:
+		ve.Add(field+".poll", "is only allowed in poll mode")
+	case c.Poll != nil:
+		c.Poll.validate(ve, field+".poll")
+	}
+}
+
+func (p *Poll) validate(ve *errors.ValidationErrorBuilder, field string) {
+	switch {
+	case secrets.HasRefs(p.StatusURL):
+		ve.Add(field+".statusUrl", "secret references are not allowed in statusUrl")
+	case !placeholdersInPath(p.StatusURL):
+		ve.Add(field+".statusUrl", "placeholders are only allowed after the scheme and host")
+	default:
+		if _, err := url.Parse(placeholderPattern.ReplaceAllString(p.StatusURL, "x")); err != nil {
+			ve.Add(field+".statusUrl", "must be a valid url")
+		}
+	}
+	if p.IntervalSeconds < 0 || p.IntervalSeconds > MaxPollInterval {
+		ve.Add(field+".intervalSeconds", fmt.Sprintf("must be between 0 and %d", MaxPollInterval))
+	}
+	p.Success.validate(ve, field+".success", true)
+	p.Failure.validate(ve, field+".failure", false)
+}
+
+func (c PollCondition) validate(ve *errors.ValidationErrorBuilder, field string, required bool) {
+	if c.Path == "" && len(c.Values) == 0 && !required {
+		return
+	}
+	helpers.ValidateRequiredString(ve, field+".path", c.Path)
+	if len(c.Values) == 0 {
+		ve.Add(field+".values", "cannot be empty")
+	}
+}
+
+// Matches reports whether the status document matches the condition, and
+// the value found at its path.
+func (c PollCondition) Matches(doc any) (string, bool) {
+	if c.Path == "" {
+		return "", false
+	}
+	v, ok := lookupPath(doc, c.Path)
+	if !ok {
+		return "", false
+	}
+	return v, slices.Contains(c.Values, v)
+}
+
+// placeholdersInPath reports whether every placeholder of a status URL comes
+// after its scheme and host, so that the response cannot choose where the
+// status is fetched from.
+func placeholdersInPath(statusURL string) bool {
+	loc := placeholderPattern.FindStringIndex(statusURL)
+	if loc == nil {
+		return true
+	}
+	prefix := statusURL[:loc[0]]
+	if i := strings.Index(prefix, "://"); i >= 0 {
+		prefix = prefix[i+3:]
+	} else if strings.HasPrefix(prefix, "//") {
+		prefix = prefix[2:]
+	}
+	return strings.ContainsAny(prefix, "/?#")
+}
+
+// StatusURLFor returns the URL to poll for the job accepted by a response
+// with the given decoded JSON body and Location header, resolved against
+// the task's url.
+func (p *Poll) StatusURLFor(taskURL string, doc any, location string) (string, error) {
+	raw := p.StatusURL
+	if raw == "" {
+		if location == "" {
+			return "", fmt.Errorf("response has no Location header to poll")
+		}
+		raw = location
+	}
+	var missing string
+	raw = placeholderPattern.ReplaceAllStringFunc(raw, func(m string) string {
+		path := placeholderPattern.FindStringSubmatch(m)[1]
+		v, ok := lookupPath(doc, path)
+		if !ok && missing == "" {
+			missing = path
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("response has no field %q for the status url", missing)
+	}
+
+	base, err := url.Parse(taskURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid task url: %w", err)
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid status url: %w", err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// lookupPath returns the scalar at a dot-separated path of a decoded JSON
+// document as text.
+func lookupPath(doc any, path string) (string, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return "", false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", false
+			}
+			cur = v[i]
+		default:
+			return "", false
+		}
+	}
+	switch v := cur.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// PendingCompletion is a run whose request was sent and which waits for its
+// result. Only a hash of its callback token is stored.
+type PendingCompletion struct {
+	ID             string         `json:"_id" bson:"_id"`
+	TaskID         string         `json:"taskId" bson:"taskId"`
+	Namespace      string         `json:"namespace" bson:"namespace"`
+	Trigger        Trigger        `json:"trigger" bson:"trigger"`
+	Mode           CompletionMode `json:"mode" bson:"mode"`
+	TokenHash      string         `json:"-" bson:"tokenHash"`
+	StartedAt      string         `json:"startedAt" bson:"startedAt"` // UTC
+	TimeoutSeconds int            `json:"timeoutSeconds" bson:"timeoutSeconds"`
+	Deadline       int64          `json:"deadline" bson:"deadline"` // UTC
+}
+
+type PendingCompletionList struct {
+	Waiting []PendingCompletion `json:"waiting"`
+	Count   int                 `json:"count"`
+}
+
+// CallbackResult is the result a target reports for a run.
+type CallbackResult struct {
+	Status  Outcome `json:"status"` // succeeded or failed
+	Message string  `json:"message"`
+}
+
+func (r CallbackResult) Validate() error {
+	ve := errors.ValidationErrs()
+	if r.Status != OutcomeSucceeded && r.Status != OutcomeFailed {
+		ve.Add("status", "must be one of succeeded, failed")
+	}
+	return ve.Err()
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	// Go Internal Packages
+	"testing"
+
+	// Local Packages
+	errors "scheduler/errors"
+)
+
+func TestLookupPath(t *testing.T) {
+	doc := map[string]any{
+		"state": "done",
+		"job":   map[string]any{"id": "j-1", "progress": 0.5, "final": true},
+		"items": []any{map[string]any{"id": float64(7)}, "second"},
+		"empty": nil,
+	}
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"state", "done", true},
+		{"job.id", "j-1", true},
+		{"job.progress", "0.5", true},
+		{"job.final", "true", true},
+		{"items.0.id", "7", true},
+		{"items.1", "second", true},
+		{"items.2", "", false},
+		{"items.-1", "", false},
+		{"items.x", "", false},
+		{"job", "", false},
+		{"empty", "", false},
+		{"missing", "", false},
+		{"state.x", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := lookupPath(doc, tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("lookupPath(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStatusURLFor(t *testing.T) {
+	const taskURL = "https://api.example.com/v1/exports?format=csv"
+	doc := map[string]any{"job": map[string]any{"id": "j-1"}, "jobs": []any{"a", "b"}}
+
+	tests := []struct {
+		name      string
+		statusURL string
+		location  string
+		want      string
+		wantErr   bool
+	}{
+		{"absolute", "https://status.example.com/jobs/{{job.id}}", "", "https://status.example.com/jobs/j-1", false},
+		{"root relative", "/jobs/{{job.id}}", "", "https://api.example.com/jobs/j-1", false},
+		{"path relative", "exports/{{ job.id }}", "", "https://api.example.com/v1/exports/j-1", false},
+		{"array element", "/jobs/{{jobs.1}}", "", "https://api.example.com/jobs/b", false},
+		{"query placeholder", "/jobs?id={{job.id}}", "", "https://api.example.com/jobs?id=j-1", false},
+		{"location", "", "/v1/jobs/9", "https://api.example.com/v1/jobs/9", false},
+		{"absolute location", "", "https://other.example.com/jobs/9", "https://other.example.com/jobs/9", false},
+		{"no location", "", "", "", true},
+		{"missing field", "/jobs/{{job.name}}", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Poll{StatusURL: tt.statusURL}
+			got, err := p.StatusURLFor(taskURL, doc, tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StatusURLFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("StatusURLFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceholdersInPath(t *testing.T) {
+	tests := []struct {
+		statusURL string
+		want      bool
+	}{
+		{"", true},
+		{"https://api.example.com/jobs/1", true},
+		{"https://api.example.com/jobs/{{job.id}}", true},
+		{"https://api.example.com?id={{job.id}}", true},
+		{"//api.example.com/{{job.id}}", true},
+		{"/jobs/{{job.id}}", true},
+		{"jobs/{{job.id}}", true},
+		{"{{job.url}}", false},
+		{"https://{{job.host}}/jobs", false},
+		{"https://api.example.com{{job.path}}", false},
+		{"https://api.{{job.region}}.example.com/jobs", false},
+		{"//{{job.host}}/jobs", false},
+		{"http{{job.tls}}://api.example.com/jobs", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.statusURL, func(t *testing.T) {
+			if got := placeholdersInPath(tt.statusURL); got != tt.want {
+				t.Errorf("placeholdersInPath(%q) = %v, want %v", tt.statusURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionValidateCallbacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		c         *Completion
+		callbacks bool
+		wantErr   bool
+	}{
+		{"callback configured", &Completion{Mode: CompletionCallback}, true, false},
+		{"callback without base url", &Completion{Mode: CompletionCallback}, false, true},
+		{"sync without base url", &Completion{Mode: CompletionSync}, false, false},
+		{"poll without base url", &Completion{Mode: CompletionPoll, Poll: &Poll{Success: PollCondition{Path: "state", Values: []string{"done"}}}}, false, false},
+		{"none", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ve := errors.ValidationErrs()
+			tt.c.normalize()
+			tt.c.validate(ve, "completion", tt.callbacks)
+			if err := ve.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	// Go Internal Packages
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	// Local Packages
+	errors "scheduler/errors"
+	secrets "scheduler/utils/secrets"
+)
+
+// DeadLetter is an execution that failed after exhausting its attempts, kept
+// so that it can be inspected and replayed.
+type DeadLetter struct {
+	ID        string          `json:"_id" bson:"_id"`
+	TaskID    string          `json:"taskId" bson:"taskId"`
+	Namespace string          `json:"namespace" bson:"namespace"`
+	TaskType  string          `json:"taskType" bson:"taskType"`
+	Trigger   Trigger         `json:"trigger" bson:"trigger"`
+	Request   Data            `json:"request" bson:"request"` // as defined; secret references are not resolved
+	Attempts  []AttemptRecord `json:"attempts" bson:"attempts"`
+	StartedAt string          `json:"startedAt" bson:"startedAt"` // UTC
+	FailedAt  string          `json:"failedAt" bson:"failedAt"`   // UTC
+	Error     string          `json:"error" bson:"error"`         // of the last attempt
+	ExpireAt  time.Time       `json:"-" bson:"expireAt"`
+}
+
+// AttemptRecord is the response to, or error of, one HTTP attempt.
+type AttemptRecord struct {
+	Attempt      int    `json:"attempt" bson:"attempt"`
+	StartedAt    string `json:"startedAt" bson:"startedAt"` // UTC
+	EndedAt      string `json:"endedAt" bson:"endedAt"`     // UTC
+	StatusCode   int    `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	ResponseBody string `json:"responseBody,omitempty" bson:"responseBody,omitempty"` // truncated
+	Error        string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+type DeadLetterList struct {
+	DeadLetters []DeadLetter `json:"deadLetters"`
+	Count       int          `json:"count"`
+}
+
+// ReplayRequest optionally edits the dead-lettered request before it is sent
+// again. Each set field replaces the stored one as a whole.
+type ReplayRequest struct {
+	Headers     map[string]string `json:"headers"`
+	QueryParams map[string]any    `json:"queryParams"`
+	RequestBody map[string]any    `json:"requestBody"`
+	RawBody     *string           `json:"rawBody"`
+	Files       []File            `json:"files"`
+}
+
+// Apply returns the request with the edits applied.
+func (r ReplayRequest) Apply(d Data) Data {
+	if r.Headers != nil {
+		d.Headers = r.Headers
+	}
+	if r.QueryParams != nil {
+		d.QueryParams = r.QueryParams
+	}
+	if r.RequestBody != nil {
+		d.RequestBody = r.RequestBody
+	}
+	if r.RawBody != nil {
+		d.RawBody = *r.RawBody
+	}
+	if r.Files != nil {
+		d.Files = r.Files
+	}
+	return d
+}
+
+// Validate checks the secret references of the edits. The url and method
+// cannot be edited, so the stored request's destination is kept. Whether the
+// edited query params and body suit the request's query encoding and body type
+// is checked once the edits are applied.
+func (r ReplayRequest) Validate() error {
+	ve := errors.ValidationErrs()
+	for key, value := range r.Headers {
+		if err := secrets.ValidateRefs(value); err != nil {
+			ve.Add("headers."+key, err.Error())
+		}
+	}
+	validateSecretRefs(ve, "queryParams", r.QueryParams)
+	validateSecretRefs(ve, "requestBody", r.RequestBody)
+	if r.RawBody != nil {
+		if err := secrets.ValidateRefs(*r.RawBody); err != nil {
+			ve.Add("rawBody", err.Error())
+		}
+	}
+	return ve.Err()
+}
+
+// CheckSecretRefs rejects edits that reference a secret the stored request
+// does not. Replaying needs only the execute scope, which must not be enough
+// to send any secret to the stored request's destination.
+func (r ReplayRequest) CheckSecretRefs(stored Data) error {
+	allowed := map[string]bool{}
+	collectSecretRefs(allowed, stored.Headers)
+	collectSecretRefs(allowed, stored.QueryParams)
+	collectSecretRefs(allowed, stored.RequestBody)
+	collectSecretRefs(allowed, stored.RawBody)
+
+	used := map[string]bool{}
+	collectSecretRefs(used, r.Headers)
+	collectSecretRefs(used, r.QueryParams)
+	collectSecretRefs(used, r.RequestBody)
+	if r.RawBody != nil {
+		collectSecretRefs(used, *r.RawBody)
+	}
+
+	var unknown []string
+	for name := range used {
+		if !allowed[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return errors.NewError(errors.Forbidden,
+			fmt.Sprintf("replay edits may only reference secrets the stored request references: %q", unknown))
+	}
+	return nil
+}
+
+// collectSecretRefs adds the secret names referenced in the strings of value
+// to names. Values decoded from Mongo hold their own array types, so
+// containers are matched on kind.
+func collectSecretRefs(names map[string]bool, value any) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		for _, name := range secrets.RefNames(rv.String()) {
+			names[name] = true
+		}
+	case reflect.Map:
+		for iter := rv.MapRange(); iter.Next(); {
+			collectSecretRefs(names, iter.Value().Interface())
+		}
+	case reflect.Slice:
+		for i := range rv.Len() {
+			collectSecretRefs(names, rv.Index(i).Interface())
+		}
+	}
+}
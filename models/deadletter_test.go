@@ -0,0 +1,48 @@
+package models
+
+import (
+	// Go Internal Packages
+	"testing"
+
+	// Local Packages
+	errors "scheduler/errors"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestReplayRequestCheckSecretRefs(t *testing.T) {
+	stored := Data{
+		Headers:     map[string]string{"Authorization": `Bearer {{secret "api-token"}}`},
+		QueryParams: map[string]any{"keys": bson.A{`{{secret "query-key"}}`}},
+	}
+	raw := `token={{secret "db-password"}}`
+	allowedRaw := `token={{ secret "api-token" }}`
+
+	tests := []struct {
+		name    string
+		edit    ReplayRequest
+		wantErr bool
+	}{
+		{"no edits", ReplayRequest{}, false},
+		{"edit without refs", ReplayRequest{Headers: map[string]string{"X-Trace": "1"}}, false},
+		{"stored header ref", ReplayRequest{RequestBody: map[string]any{"token": `{{secret "api-token"}}`}}, false},
+		{"stored nested ref", ReplayRequest{Headers: map[string]string{"X-Key": `{{secret "query-key"}}`}}, false},
+		{"stored ref in raw body", ReplayRequest{RawBody: &allowedRaw}, false},
+		{"new header ref", ReplayRequest{Headers: map[string]string{"Authorization": `{{secret "admin-token"}}`}}, true},
+		{"new nested ref", ReplayRequest{RequestBody: map[string]any{"a": []any{map[string]any{"b": `{{secret "other"}}`}}}}, true},
+		{"new ref in raw body", ReplayRequest{RawBody: &raw}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.edit.CheckSecretRefs(stored)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckSecretRefs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var e *errors.Error
+			if err != nil && (!errors.As(err, &e) || e.Kind != errors.Forbidden) {
+				t.Errorf("CheckSecretRefs() error = %v, want a Forbidden error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+// Job is a fire waiting in the execution queue for a worker. A job is visible
+// to workers while LeaseUntil is in the past; a worker leases it by pushing
+// LeaseUntil forward and keeps extending the lease while the run lasts, so the
+// job is delivered again if the worker dies.
+type Job struct {
+	ID         string  `json:"_id" bson:"_id"`
+	TaskID     string  `json:"taskId" bson:"taskId"`
+	Namespace  string  `json:"namespace" bson:"namespace"`
+	Trigger    Trigger `json:"trigger" bson:"trigger"`
+	FireAt     int64   `json:"fireAt" bson:"fireAt"`         // UTC; when the fire was due
+	EnqueuedAt string  `json:"enqueuedAt" bson:"enqueuedAt"` // UTC
+	LeasedBy   string  `json:"leasedBy" bson:"leasedBy"`     // worker ID of the current lease
+	LeaseUntil int64   `json:"leaseUntil" bson:"leaseUntil"` // UTC; 0 until first leased
+	Deliveries int     `json:"deliveries" bson:"deliveries"`
+	// Data replaces the request of the task, e.g. for a dead letter replay.
+	Data *Data `json:"data,omitempty" bson:"data,omitempty"`
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	// Go Internal Packages
+	"fmt"
+	"regexp"
+	"strings"
+
+	// Local Packages
+	errors "scheduler/errors"
+)
+
+// Label keys may not contain "." or "$" so they can be used directly as
+// document field paths ("labels.<key>").
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_/]{0,61}[A-Za-z0-9])?$`)
+	labelValuePattern = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// Labels that sync sets on the tasks it owns. No other label may use the
+// reserved prefix.
+const (
+	ReservedLabelPrefix = "scheduler/"
+	LabelManagedBy      = ReservedLabelPrefix + "managed-by"
+	LabelSyncName       = ReservedLabelPrefix + "name"
+	LabelSpecHash       = ReservedLabelPrefix + "spec-hash"
+	ManagedBySync       = "sync"
+)
+
+type SelectorOp string
+
+const (
+	SelectorEquals    SelectorOp = "="
+	SelectorNotEquals SelectorOp = "!="
+	SelectorExists    SelectorOp = "exists"
+	SelectorNotExists SelectorOp = "!exists"
+)
+
+// Requirement is a single term of a label selector.
+type Requirement struct {
+	Key   string     `json:"key"`
+	Op    SelectorOp `json:"op"`
+	Value string     `json:"value,omitempty"`
+}
+
+// Selector matches tasks whose labels satisfy every requirement.
+type Selector []Requirement
+
+// ParseSelector parses a comma separated selector such as
+// "team=billing,env!=prod,tier,!legacy".
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var req Requirement
+		switch {
+		case strings.Contains(term, "!="):
+			key, value, _ := strings.Cut(term, "!=")
+			req = Requirement{Key: strings.TrimSpace(key), Op: SelectorNotEquals, Value: strings.TrimSpace(value)}
+		case strings.Contains(term, "="):
+			key, value, _ := strings.Cut(term, "=")
+			req = Requirement{Key: strings.TrimSpace(key), Op: SelectorEquals, Value: strings.TrimPrefix(strings.TrimSpace(value), "=")}
+		case strings.HasPrefix(term, "!"):
+			req = Requirement{Key: strings.TrimSpace(term[1:]), Op: SelectorNotExists}
+		default:
+			req = Requirement{Key: term, Op: SelectorExists}
+		}
+
+		if !labelKeyPattern.MatchString(req.Key) {
+			return nil, fmt.Errorf("invalid label key %q in selector", req.Key)
+		}
+		if !labelValuePattern.MatchString(req.Value) {
+			return nil, fmt.Errorf("invalid label value %q in selector", req.Value)
+		}
+		sel = append(sel, req)
+	}
+	return sel, nil
+}
+
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s))
+	for _, req := range s {
+		switch req.Op {
+		case SelectorExists:
+			terms = append(terms, req.Key)
+		case SelectorNotExists:
+			terms = append(terms, "!"+req.Key)
+		default:
+			terms = append(terms, req.Key+string(req.Op)+req.Value)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// Matches reports whether labels satisfy every requirement of the selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+		switch req.Op {
+		case SelectorEquals:
+			if !ok || value != req.Value {
+				return false
+			}
+		case SelectorNotEquals:
+			if ok && value == req.Value {
+				return false
+			}
+		case SelectorExists:
+			if !ok {
+				return false
+			}
+		case SelectorNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ValidLabelValue reports whether value can be used as a label value.
+func ValidLabelValue(value string) bool {
+	return labelValuePattern.MatchString(value)
+}
+
+// ValidateLabels checks label keys and values. Keys with the reserved prefix
+// are only accepted from sync, when syncLabels is set, and only as the full
+// set of ownership labels that sync sets.
+func ValidateLabels(ve *errors.ValidationErrorBuilder, field string, labels map[string]string, syncLabels bool) {
+	reserved := false
+	for key, value := range labels {
+		if !labelKeyPattern.MatchString(key) {
+			ve.Add(field+"."+key, "invalid label key, expected alphanumerics, '-', '_' or '/' (max 63)")
+			continue
+		}
+		if !labelValuePattern.MatchString(value) {
+			ve.Add(field+"."+key, "invalid label value, expected alphanumerics, '-', '_' or '.' (max 63)")
+		}
+		if strings.HasPrefix(key, ReservedLabelPrefix) {
+			reserved = true
+			if !syncLabels {
+				ve.Add(field+"."+key, fmt.Sprintf("the %q prefix is reserved for sync", ReservedLabelPrefix))
+				continue
+			}
+			if key != LabelManagedBy && key != LabelSyncName && key != LabelSpecHash {
+				ve.Add(field+"."+key, fmt.Sprintf("the %q prefix is reserved", ReservedLabelPrefix))
+			}
+		}
+	}
+	if reserved && syncLabels && (labels[LabelManagedBy] != ManagedBySync || labels[LabelSyncName] == "" || labels[LabelSpecHash] == "") {
+		ve.Add(field, fmt.Sprintf("labels with the reserved %q prefix are set by sync only", ReservedLabelPrefix))
+	}
+}
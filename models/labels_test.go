@@ -0,0 +1,48 @@
+package models
+
+import (
+	// Go Internal Packages
+	"testing"
+
+	// Local Packages
+	errors "scheduler/errors"
+)
+
+func TestValidateLabels(t *testing.T) {
+	synced := map[string]string{LabelManagedBy: ManagedBySync, LabelSyncName: "nightly", LabelSpecHash: "0123456789abcdef"}
+	with := func(key, value string) map[string]string {
+		labels := map[string]string{key: value}
+		for k, v := range synced {
+			labels[k] = v
+		}
+		return labels
+	}
+
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		sync    bool
+		wantErr bool
+	}{
+		{"none", nil, false, false},
+		{"plain", map[string]string{"team": "billing", "example/tier": "gold"}, false, false},
+		{"invalid key", map[string]string{"a.b": "x"}, false, true},
+		{"invalid value", map[string]string{"team": "a b"}, false, true},
+		{"sync ownership labels", synced, true, false},
+		{"sync ownership labels outside sync", synced, false, true},
+		{"sync labels plus own", with("team", "billing"), true, false},
+		{"unknown reserved key", with("scheduler/owner", "me"), true, true},
+		{"reserved key alone", map[string]string{"scheduler/owner": "me"}, true, true},
+		{"partial ownership labels", map[string]string{LabelManagedBy: ManagedBySync}, true, true},
+		{"foreign manager", map[string]string{LabelManagedBy: "other", LabelSyncName: "nightly", LabelSpecHash: "0123"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ve := errors.ValidationErrs()
+			ValidateLabels(ve, "labels", tt.labels, tt.sync)
+			if err := ve.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLabels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	// Go Internal Packages
+	"regexp"
+)
+
+// DefaultNamespace holds tasks created through the un-namespaced routes.
+const DefaultNamespace = "default"
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// NamespaceOrDefault returns ns, or DefaultNamespace if ns is empty.
+func NamespaceOrDefault(ns string) string {
+	if ns == "" {
+		return DefaultNamespace
+	}
+	return ns
+}
+
+// ValidNamespace reports whether ns is a lowercase DNS label.
+func ValidNamespace(ns string) bool {
+	return namespacePattern.MatchString(ns)
+}
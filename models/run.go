@@ -0,0 +1,63 @@
+package models
+
+// Outcome is the result of a single run of a task.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded"
+	OutcomeFailed    Outcome = "failed"
+	OutcomeCancelled Outcome = "cancelled" // stopped through the API; no alert is sent
+	OutcomeSkipped   Outcome = "skipped"   // not started, e.g. the namespace quota was exhausted
+	// OutcomeShortCircuited is recorded when the circuit of the target host is
+	// open; no request is sent and no alert is sent.
+	OutcomeShortCircuited Outcome = "short_circuited"
+)
+
+// Ran reports whether a run with this outcome started. A skipped or
+// short-circuited run sent no request and does not count as an execution.
+func (o Outcome) Ran() bool {
+	return o == OutcomeSucceeded || o == OutcomeFailed || o == OutcomeCancelled
+}
+
+// Trigger is what started a run.
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerManual   Trigger = "manual" // execute-now, single or bulk
+	TriggerReplay   Trigger = "replay" // replay of a dead letter
+)
+
+// RunInfo describes an execution that is in flight on this instance.
+type RunInfo struct {
+	RunID          string  `json:"runId"`
+	TaskID         string  `json:"taskId"`
+	Namespace      string  `json:"namespace"`
+	Trigger        Trigger `json:"trigger"`
+	StartedAt      string  `json:"startedAt"` // UTC
+	RunningSeconds int64   `json:"runningSeconds"`
+	Attempt        int     `json:"attempt"` // 0 until the first HTTP attempt starts
+	MaxAttempts    int     `json:"maxAttempts"`
+	NextAttemptAt  string  `json:"nextAttemptAt,omitempty"` // UTC; set while waiting out a retry backoff, a rate limit or the next status poll
+}
+
+type RunList struct {
+	Runs  []RunInfo `json:"runs"`
+	Count int       `json:"count"`
+}
+
+// PoolStats describes the execution pool of this instance.
+type PoolStats struct {
+	MaxConcurrent     int                  `json:"maxConcurrent"` // 0 is unlimited
+	MaxPerHost        int                  `json:"maxPerHost"`    // 0 is unlimited
+	Running           int                  `json:"running"`
+	Queued            int                  `json:"queued"`
+	OldestWaitSeconds int64                `json:"oldestWaitSeconds"` // of the longest-queued fire
+	Hosts             map[string]HostStats `json:"hosts"`
+}
+
+// HostStats counts the executions of one target host.
+type HostStats struct {
+	Running int `json:"running"`
+	Queued  int `json:"queued"`
+}
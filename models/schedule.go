@@ -0,0 +1,199 @@
+package models
+
+import (
+	// Go Internal Packages
+	"time"
+
+	// Local Packages
+	helpers "scheduler/utils/helpers"
+)
+
+const (
+	DefaultPreviewCount = 10
+	MaxPreviewCount     = 100
+
+	// SkipRetryDelay is how long a non-recurring task waits to be fired
+	// again after its fire was skipped, in seconds.
+	SkipRetryDelay = 60
+)
+
+// NextFireAt returns when the scheduler fires the task next, at or after
+// curUnix, ignoring the enable flag. This is the single source of the
+// scheduling rules:
+//   - a task whose start is still ahead fires at its start time
+//   - a missed non-recurring task that never ran fires immediately
+//   - a recurring task fires on the first multiple of Recur after its start
+//     that is at or after curUnix
+//
+// ok is false when the task will not fire again before it expires.
+func (t *Task) NextFireAt(curUnix helpers.Unix) (next helpers.Unix, ok bool) {
+	startUnix := helpers.Unix(t.StartUnix)
+	endUnix := helpers.Unix(t.EndUnix)
+
+	switch {
+	case curUnix > endUnix:
+		return 0, false
+	case !t.IsRecurEnabled && t.Status.IsAlreadyExecuted():
+		return 0, false
+	case curUnix <= startUnix:
+		return startUnix, true
+	case !t.IsRecurEnabled && t.Recur == 0:
+		return curUnix, true
+	case t.Recur <= 0:
+		return 0, false
+	}
+
+	interval := helpers.Unix(t.Recur)
+	next = startUnix + (curUnix-startUnix+interval-1)/interval*interval
+	if next > endUnix {
+		return 0, false
+	}
+	return next, true
+}
+
+// ScheduleNext sets NextRunAt to the next fire time at or after curUnix, or to
+// zero when the task is disabled or will not fire again.
+func (t *Task) ScheduleNext(curUnix helpers.Unix) {
+	t.NextRunAt = 0
+	if !t.Enable {
+		return
+	}
+	if next, ok := t.NextFireAt(curUnix); ok {
+		t.NextRunAt = int64(next)
+	}
+}
+
+// NextRunAfterFire returns the NextRunAt of the task once the fire due at or
+// before curUnix has started: the following fire of a recurring task, or zero.
+// Fires missed beyond the one being started are skipped.
+func (t *Task) NextRunAfterFire(curUnix helpers.Unix) int64 {
+	if !t.IsRecurEnabled {
+		return 0
+	}
+	next, ok := t.NextFireAt(curUnix + 1)
+	if !ok {
+		return 0
+	}
+	return int64(next)
+}
+
+// FireClaim records a fire that an instance claimed and executes in process.
+// The instance refreshes HeartbeatAt while the run is in flight and removes
+// the claim once it ends, so a claim that is no longer refreshed belongs to
+// an instance that died, and its fire is given back.
+type FireClaim struct {
+	FireAt      int64  `json:"fireAt" bson:"fireAt"` // UTC
+	Instance    string `json:"instance" bson:"instance"`
+	HeartbeatAt int64  `json:"heartbeatAt" bson:"heartbeatAt"` // UTC
+}
+
+// RetryAfterSkip returns when a non-recurring task fires again after its fire
+// was skipped at curUnix. ok is false for a recurring task, which waits for
+// its next fire, and for a task that ran already or expires before then.
+func (t *Task) RetryAfterSkip(curUnix helpers.Unix) (next helpers.Unix, ok bool) {
+	if t.IsRecurEnabled || t.Status.IsAlreadyExecuted() {
+		return 0, false
+	}
+	next = curUnix + SkipRetryDelay
+	if next > helpers.Unix(t.EndUnix) {
+		return 0, false
+	}
+	return next, true
+}
+
+// FireTimes returns up to n fire times at or after curUnix. Recurring tasks
+// repeat every Recur seconds from their next fire until they expire.
+func (t *Task) FireTimes(curUnix helpers.Unix, n int) []helpers.Unix {
+	next, ok := t.NextFireAt(curUnix)
+	if !ok || n <= 0 {
+		return nil
+	}
+	times := []helpers.Unix{next}
+	if !t.IsRecurEnabled {
+		return times
+	}
+	endUnix := helpers.Unix(t.EndUnix)
+	for len(times) < n {
+		next += helpers.Unix(t.Recur)
+		if next > endUnix {
+			break
+		}
+		times = append(times, next)
+	}
+	return times
+}
+
+// FireTimesBetween returns the fire times in [from, to) as the scheduler sees
+// the task at curUnix. Windows starting before curUnix are not clamped; pass
+// from >= curUnix to list only fires that are still to come.
+func (t *Task) FireTimesBetween(curUnix, from, to helpers.Unix) []helpers.Unix {
+	next, ok := t.NextFireAt(curUnix)
+	if !ok || next >= to {
+		return nil
+	}
+	if !t.IsRecurEnabled {
+		if next < from {
+			return nil
+		}
+		return []helpers.Unix{next}
+	}
+
+	interval := helpers.Unix(t.Recur)
+	if next < from {
+		next += (from - next + interval - 1) / interval * interval
+	}
+	end := min(to, helpers.Unix(t.EndUnix)+1)
+	var times []helpers.Unix
+	for ; next < end; next += interval {
+		times = append(times, next)
+	}
+	return times
+}
+
+type FireTime struct {
+	Unix  int64  `json:"unix"`
+	UTC   string `json:"utc"`
+	Local string `json:"local"` // in the task's schedule zone (IST)
+}
+
+type Preview struct {
+	TaskID    string     `json:"taskId,omitempty"`
+	Enabled   bool       `json:"enabled"`
+	Timezone  string     `json:"timezone"`
+	Reason    string     `json:"reason,omitempty"` // why there are no fire times
+	FireTimes []FireTime `json:"fireTimes"`
+}
+
+// PreviewFireTimes lists the next n fire times of the task, none when it is disabled.
+func (t *Task) PreviewFireTimes(curUnix helpers.Unix, n int) (*Preview, error) {
+	loc, err := time.LoadLocation(helpers.ISTZone)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Preview{TaskID: t.ID, Enabled: t.Enable, Timezone: helpers.ISTZone, FireTimes: []FireTime{}}
+	switch {
+	case !t.Enable:
+		p.Reason = "task is disabled"
+		return p, nil
+	case curUnix > helpers.Unix(t.EndUnix):
+		p.Reason = "task has expired"
+		return p, nil
+	}
+
+	for _, at := range t.FireTimes(curUnix, n) {
+		utc := at.Time()
+		p.FireTimes = append(p.FireTimes, FireTime{
+			Unix:  int64(at),
+			UTC:   utc.Format(time.RFC3339),
+			Local: utc.In(loc).Format(time.RFC3339),
+		})
+	}
+	if len(p.FireTimes) == 0 {
+		p.Reason = "task will not fire again before it expires"
+		if !t.IsRecurEnabled && t.Status.IsAlreadyExecuted() {
+			p.Reason = "non-recurring task already executed"
+		}
+	}
+	return p, nil
+}
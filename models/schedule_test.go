@@ -0,0 +1,103 @@
+package models
+
+import (
+	// Go Internal Packages
+	"slices"
+	"testing"
+
+	// Local Packages
+	helpers "scheduler/utils/helpers"
+)
+
+func TestNextFireAt(t *testing.T) {
+	const start, end = 1000, 5000
+	ran := Status{LastExecutedAt: "2026-01-01T00:00:00Z", Outcome: OutcomeSucceeded}
+
+	tests := []struct {
+		name     string
+		task     Task
+		curUnix  helpers.Unix
+		wantNext helpers.Unix
+		wantOK   bool
+	}{
+		{"one-time before start", Task{StartUnix: start, EndUnix: end}, 500, start, true},
+		{"one-time at start", Task{StartUnix: start, EndUnix: end}, start, start, true},
+		{"one-time missed fires now", Task{StartUnix: start, EndUnix: end}, 1500, 1500, true},
+		{"one-time already executed", Task{StartUnix: start, EndUnix: end, Status: ran}, 500, 0, false},
+		{"one-time skipped still fires", Task{StartUnix: start, EndUnix: end, Status: Status{Outcome: OutcomeSkipped}}, 1500, 1500, true},
+		{"one-time short-circuited still fires", Task{StartUnix: start, EndUnix: end, Status: Status{Outcome: OutcomeShortCircuited}}, 1500, 1500, true},
+		{"expired", Task{StartUnix: start, EndUnix: end}, end + 1, 0, false},
+		{"recurring before start", Task{StartUnix: start, EndUnix: end, Recur: 100, IsRecurEnabled: true}, 0, start, true},
+		{"recurring between fires", Task{StartUnix: start, EndUnix: end, Recur: 100, IsRecurEnabled: true}, 1050, 1100, true},
+		{"recurring on a fire fires now", Task{StartUnix: start, EndUnix: end, Recur: 100, IsRecurEnabled: true}, 1100, 1100, true},
+		{"recurring just past a fire", Task{StartUnix: start, EndUnix: end, Recur: 100, IsRecurEnabled: true}, 1101, 1200, true},
+		{"recurring last fire on end", Task{StartUnix: start, EndUnix: 1200, Recur: 100, IsRecurEnabled: true}, 1200, 1200, true},
+		{"recurring ignores past runs", Task{StartUnix: start, EndUnix: end, Recur: 100, IsRecurEnabled: true, Status: ran}, 1050, 1100, true},
+		{"recurring next past end", Task{StartUnix: start, EndUnix: 1150, Recur: 100, IsRecurEnabled: true}, 1120, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := tt.task.NextFireAt(tt.curUnix)
+			if next != tt.wantNext || ok != tt.wantOK {
+				t.Errorf("NextFireAt(%d) = %d, %v; want %d, %v", tt.curUnix, next, ok, tt.wantNext, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryAfterSkip(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     Task
+		curUnix  helpers.Unix
+		wantNext helpers.Unix
+		wantOK   bool
+	}{
+		{"skipped one-time", Task{EndUnix: 5000, Status: Status{Outcome: OutcomeSkipped}}, 1000, 1000 + SkipRetryDelay, true},
+		{"expires before retry", Task{EndUnix: 1000 + SkipRetryDelay - 1}, 1000, 0, false},
+		{"ran before", Task{EndUnix: 5000, Status: Status{LastExecutedAt: "2026-01-01T00:00:00Z"}}, 1000, 0, false},
+		{"recurring", Task{EndUnix: 5000, Recur: 100, IsRecurEnabled: true}, 1000, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := tt.task.RetryAfterSkip(tt.curUnix)
+			if next != tt.wantNext || ok != tt.wantOK {
+				t.Errorf("RetryAfterSkip(%d) = %d, %v; want %d, %v", tt.curUnix, next, ok, tt.wantNext, tt.wantOK)
+			}
+			// The retry is a fire the task still has.
+			if ok {
+				if fire, fireOK := tt.task.NextFireAt(next); !fireOK || fire != next {
+					t.Errorf("NextFireAt(%d) = %d, %v; want the retry", next, fire, fireOK)
+				}
+			}
+		})
+	}
+}
+
+func TestFireTimesBetween(t *testing.T) {
+	recurring := Task{StartUnix: 1000, EndUnix: 2000, Recur: 100, IsRecurEnabled: true}
+	oneTime := Task{StartUnix: 1000, EndUnix: 2000}
+
+	tests := []struct {
+		name     string
+		task     Task
+		curUnix  helpers.Unix
+		from, to helpers.Unix
+		want     []helpers.Unix
+	}{
+		{"recurring window", recurring, 0, 1050, 1300, []helpers.Unix{1100, 1200}},
+		{"recurring from inclusive, to exclusive", recurring, 0, 1100, 1200, []helpers.Unix{1100}},
+		{"recurring stops at end", recurring, 0, 1850, 5000, []helpers.Unix{1900, 2000}},
+		{"recurring only future fires", recurring, 1150, 1000, 1400, []helpers.Unix{1200, 1300}},
+		{"recurring expired", recurring, 2001, 0, 3000, nil},
+		{"one-time in window", oneTime, 0, 900, 1100, []helpers.Unix{1000}},
+		{"one-time outside window", oneTime, 0, 1001, 1100, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.FireTimesBetween(tt.curUnix, tt.from, tt.to); !slices.Equal(got, tt.want) {
+				t.Errorf("FireTimesBetween(%d, %d, %d) = %v, want %v", tt.curUnix, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
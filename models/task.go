@@ -2,7 +2,10 @@ package models
 
 import (
 	// Go Internal Packages
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	errors "scheduler/errors"
 	helpers "scheduler/utils/helpers"
 	httpclient "scheduler/utils/httpclient"
+	secrets "scheduler/utils/secrets"
 )
 
 type Data struct {
@@ -19,55 +23,193 @@ type Data struct {
 	QueryParams map[string]any    `json:"queryParams" bson:"queryParams"`
 	Headers     map[string]string `json:"headers" bson:"headers"`
 	RequestBody map[string]any    `json:"requestBody" bson:"requestBody"`
+	// QueryEncoding selects how nested query param values are encoded; by
+	// default only scalars and arrays of scalars are allowed.
+	QueryEncoding httpclient.QueryEncoding `json:"queryEncoding,omitempty" bson:"queryEncoding,omitempty"`
+	// BodyType selects how the body is encoded; default json. json, form and
+	// multipart use RequestBody, raw and binary use RawBody.
+	BodyType httpclient.BodyType `json:"bodyType,omitempty" bson:"bodyType,omitempty"`
+	RawBody  string              `json:"rawBody,omitempty" bson:"rawBody,omitempty"` // text, or base64 for binary
+	Files    []File              `json:"files,omitempty" bson:"files,omitempty"`     // multipart only
+}
+
+// File is a file part of a multipart body.
+type File struct {
+	Field       string `json:"field" bson:"field"`
+	Filename    string `json:"filename" bson:"filename"`
+	ContentType string `json:"contentType,omitempty" bson:"contentType,omitempty"` // default application/octet-stream
+	Content     string `json:"content" bson:"content"`                             // base64
+}
+
+// TargetHost returns the hostname the task calls, or "unknown" when the url
+// cannot be parsed.
+func (d Data) TargetHost() string {
+	u, err := url.Parse(d.URL)
+	if err != nil || u.Hostname() == "" {
+		return "unknown"
+	}
+	return u.Hostname()
+}
+
+// RateLimit limits how often a task calls its target: at most Requests
+// requests every PerSeconds, with bursts of up to Burst requests.
+type RateLimit struct {
+	Requests   int `json:"requests" bson:"requests"`
+	PerSeconds int `json:"perSeconds" bson:"perSeconds"`
+	Burst      int `json:"burst,omitempty" bson:"burst,omitempty"` // default 1
+}
+
+// Interval returns the time one request uses up of the limit.
+func (r RateLimit) Interval() time.Duration {
+	return time.Duration(r.PerSeconds) * time.Second / time.Duration(r.Requests)
+}
+
+func (r *RateLimit) validate(ve *errors.ValidationErrorBuilder, field string) {
+	if r == nil {
+		return
+	}
+	if r.Requests <= 0 {
+		ve.Add(field+".requests", "must be greater than 0")
+	}
+	if r.PerSeconds <= 0 {
+		ve.Add(field+".perSeconds", "must be greater than 0")
+	}
+	if r.Burst < 0 {
+		ve.Add(field+".burst", "cannot be negative")
+	}
 }
 
 type Status struct {
-	LastExecutedAt   string `json:"lastExecutedAt" bson:"lastExecutedAt"` // UTC
-	IsComplete       bool   `json:"isComplete" bson:"isComplete"`
-	ExceptionMessage string `json:"exceptionMessage" bson:"exceptionMessage"`
+	LastExecutedAt   string  `json:"lastExecutedAt" bson:"lastExecutedAt"` // UTC
+	IsComplete       bool    `json:"isComplete" bson:"isComplete"`
+	Outcome          Outcome `json:"outcome,omitempty" bson:"outcome,omitempty"` // of the last run
+	ExceptionMessage string  `json:"exceptionMessage" bson:"exceptionMessage"`
 }
 
 type Task struct {
-	ID               string `json:"_id" bson:"_id"`
-	Schedule         string `json:"schedule" bson:"schedule"`
-	Enable           bool   `json:"enable" bson:"enable"`
-	ScheduleDate     string `json:"scheduleDate" bson:"scheduleDate"` // IST
-	ScheduleTime     string `json:"scheduleTime" bson:"scheduleTime"` // IST
-	Recur            int    `json:"recur" bson:"recur"`
-	IsRecurEnabled   bool   `json:"isRecurEnabled" bson:"isRecurEnabled"`
-	NumberOfAttempts int    `json:"numberOfAttempts" bson:"numberOfAttempts"`
-	CreatedAt        string `json:"createdAt" bson:"createdAt"` // UTC
-	UpdatedAt        string `json:"updatedAt" bson:"updatedAt"` // UTC
-	ExpiresAt        string `json:"expiresAt" bson:"expiresAt"` // UTC
-	StartUnix        int64  `json:"startUnix" bson:"startUnix"` // UTC
-	EndUnix          int64  `json:"endUnix" bson:"endUnix"`     // UTC
-	TaskData         Data   `json:"taskData" bson:"taskData"`
-	Status           Status `json:"status" bson:"status"`
+	ID               string            `json:"_id" bson:"_id"`
+	Namespace        string            `json:"namespace" bson:"namespace"`
+	ExternalID       string            `json:"externalId,omitempty" bson:"externalId,omitempty"`
+	RequestHash      string            `json:"requestHash,omitempty" bson:"requestHash,omitempty"` // fingerprint of the creating request
+	Labels           map[string]string `json:"labels" bson:"labels"`
+	Schedule         string            `json:"schedule" bson:"schedule"`
+	Enable           bool              `json:"enable" bson:"enable"`
+	ScheduleDate     string            `json:"scheduleDate" bson:"scheduleDate"` // IST
+	ScheduleTime     string            `json:"scheduleTime" bson:"scheduleTime"` // IST
+	Recur            int               `json:"recur" bson:"recur"`
+	IsRecurEnabled   bool              `json:"isRecurEnabled" bson:"isRecurEnabled"`
+	NumberOfAttempts int               `json:"numberOfAttempts" bson:"numberOfAttempts"`
+	RateLimit        *RateLimit        `json:"rateLimit,omitempty" bson:"rateLimit,omitempty"`
+	Completion       *Completion       `json:"completion,omitempty" bson:"completion,omitempty"`
+	CreatedAt        string            `json:"createdAt" bson:"createdAt"` // UTC
+	UpdatedAt        string            `json:"updatedAt" bson:"updatedAt"` // UTC
+	CreatedBy        string            `json:"createdBy" bson:"createdBy"`
+	UpdatedBy        string            `json:"updatedBy" bson:"updatedBy"`
+	ExpiresAt        string            `json:"expiresAt" bson:"expiresAt"` // UTC
+	StartUnix        int64             `json:"startUnix" bson:"startUnix"` // UTC
+	EndUnix          int64             `json:"endUnix" bson:"endUnix"`     // UTC
+	NextRunAt        int64             `json:"nextRunAt" bson:"nextRunAt"` // UTC; 0 when no fire is pending
+	Claim            *FireClaim        `json:"claim,omitempty" bson:"claim,omitempty"`
+	TaskData         Data              `json:"taskData" bson:"taskData"`
+	Status           Status            `json:"status" bson:"status"`
 }
 
 type CreateRequest struct {
-	Schedule         string `json:"schedule"`
-	Enable           bool   `json:"enable"`
-	ScheduleDate     string `json:"scheduleDate"` // IST
-	ScheduleTime     string `json:"scheduleTime"` // IST
-	Recur            int    `json:"recur"`
-	IsRecurEnabled   bool   `json:"isRecurEnabled"`
-	NumberOfAttempts int    `json:"numberOfAttempts"`
-	ExpiresAt        string `json:"expiresAt"` // UTC
-	TaskData         Data   `json:"taskData"`
-	Status           Status `json:"status"`
+	ExternalID       string            `json:"externalId"` // client-supplied idempotency key
+	RequestHash      string            `json:"-"`
+	Labels           map[string]string `json:"labels"`
+	Schedule         string            `json:"schedule"`
+	Enable           bool              `json:"enable"`
+	ScheduleDate     string            `json:"scheduleDate"` // IST
+	ScheduleTime     string            `json:"scheduleTime"` // IST
+	Recur            int               `json:"recur"`
+	IsRecurEnabled   bool              `json:"isRecurEnabled"`
+	NumberOfAttempts int               `json:"numberOfAttempts"`
+	RateLimit        *RateLimit        `json:"rateLimit,omitempty"`
+	Completion       *Completion       `json:"completion,omitempty"`
+	ExpiresAt        string            `json:"expiresAt"` // UTC
+	TaskData         Data              `json:"taskData"`
+	Status           Status            `json:"status"`
 }
 
+const maxExternalIDLen = 255
+
 type ActiveList struct {
 	ActiveTasks []string `json:"activeTasks"`
 }
 
+type TaskList struct {
+	Tasks []Task `json:"tasks"`
+	Count int    `json:"count"`
+}
+
+type BatchMode string
+
+const (
+	BatchAtomic     BatchMode = "atomic"      // create every task or none
+	BatchBestEffort BatchMode = "best_effort" // create every valid task
+)
+
+type BatchCreateRequest struct {
+	Mode  BatchMode       `json:"mode"`
+	Tasks []CreateRequest `json:"tasks"`
+}
+
+// BatchItem is a validated request together with its position in the batch.
+type BatchItem struct {
+	Index   int
+	Request CreateRequest
+}
+
+type BatchItemResult struct {
+	Index            int                     `json:"index"`
+	TaskID           string                  `json:"taskId,omitempty"`
+	ValidationErrors errors.ValidationErrors `json:"validation_errors,omitempty"`
+	Error            string                  `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Mode    BatchMode         `json:"mode"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []BatchItemResult `json:"results"`
+}
+
+type BulkAction string
+
+const (
+	BulkEnable  BulkAction = "enable"
+	BulkDisable BulkAction = "disable"
+	BulkDelete  BulkAction = "delete"
+	BulkExecute BulkAction = "execute"
+)
+
+type BulkItemResult struct {
+	TaskID string `json:"taskId"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkResult struct {
+	Action    BulkAction       `json:"action"`
+	Selector  string           `json:"selector"`
+	Matched   int              `json:"matched"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
 func (s *Status) IsAlreadyExecuted() bool {
 	return s.LastExecutedAt != ""
 }
 
 // Normalize sets defaults and normalizes fields. Call before Validate.
+// The request is fingerprinted last, so that requests differing only in
+// defaulted or normalized fields compare equal. A defaulted ExpiresAt depends
+// on the current time and is left out of the fingerprint.
 func (t *CreateRequest) Normalize() {
+	t.ExternalID = strings.TrimSpace(t.ExternalID)
+	defaultExpiry := t.ExpiresAt == ""
 	t.Schedule = strings.ToUpper(t.Schedule)
 	if t.Schedule == "" {
 		t.Schedule = "NOW"
@@ -78,11 +220,47 @@ func (t *CreateRequest) Normalize() {
 	if t.ExpiresAt == "" {
 		t.ExpiresAt = helpers.GetExpiryTime()
 	}
+	t.Completion.normalize()
+	if t.ExternalID != "" && t.RequestHash == "" {
+		t.RequestHash = t.fingerprint(defaultExpiry)
+	}
+}
+
+func (t *CreateRequest) fingerprint(defaultExpiry bool) string {
+	c := *t
+	if defaultExpiry {
+		c.ExpiresAt = ""
+	}
+	b, _ := json.Marshal(c)
+	return helpers.SHA256(string(b))
+}
+
+// TaskRules are the deployment settings a task definition is checked against.
+type TaskRules struct {
+	Policy    *httpclient.Policy // outbound url policy; nil allows every url
+	Callbacks bool               // callbacks.base_url is set, so callback completion can be used
+	Sync      bool               // the request comes from sync, which may set its ownership labels
+}
+
+// Validate checks the request. The target url must also be permitted by the rules' policy.
+func (t *CreateRequest) Validate(rules TaskRules) error {
+	return t.validate(rules, true)
+}
+
+// ValidateUpdate checks a request that replaces an existing task. Unlike for
+// a new task, the start may have passed already, e.g. for a recurring task
+// that is running; the task must still not have expired.
+func (t *CreateRequest) ValidateUpdate(rules TaskRules) error {
+	return t.validate(rules, false)
 }
 
-func (t *CreateRequest) Validate() error {
+func (t *CreateRequest) validate(rules TaskRules, isNew bool) error {
 	ve := errors.ValidationErrs()
 
+	if len(t.ExternalID) > maxExternalIDLen {
+		ve.Add("externalId", fmt.Sprintf("must be at most %d characters", maxExternalIDLen))
+	}
+	ValidateLabels(ve, "labels", t.Labels, rules.Sync)
 	helpers.ValidateDate(ve, "scheduleDate", t.ScheduleDate)
 	helpers.ValidateTime(ve, "scheduleTime", t.ScheduleTime)
 
@@ -100,14 +278,10 @@ func (t *CreateRequest) Validate() error {
 			ve.Add("expiresAt", "Invalid format, expected RFC3339 NANO")
 		}
 	}
-	helpers.ValidateRequiredString(ve, "taskData.taskType", t.TaskData.TaskType)
-	if t.TaskData.RequestType == "" {
-		ve.Add("taskData.requestType", "cannot be empty")
-	} else if err := t.TaskData.RequestType.Validate(); err != nil {
-		ve.Add("taskData.requestType", err.Error())
-	}
-	helpers.ValidateRequiredString(ve, "taskData.url", t.TaskData.URL)
-	if t.Status.LastExecutedAt != "" || t.Status.ExceptionMessage != "" {
+	t.RateLimit.validate(ve, "rateLimit")
+	t.Completion.validate(ve, "completion", rules.Callbacks)
+	t.TaskData.validate(ve, rules.Policy)
+	if t.Status.LastExecutedAt != "" || t.Status.ExceptionMessage != "" || t.Status.Outcome != "" {
 		ve.Add("status", "need to be empty for new task")
 	}
 
@@ -120,7 +294,7 @@ func (t *CreateRequest) Validate() error {
 			if err != nil {
 				ve.Add("expiresAt", "failed to parse: "+err.Error())
 			} else {
-				if helpers.Unix(startUnix) < helpers.CurrentUTCUnix() {
+				if isNew && helpers.Unix(startUnix) < helpers.CurrentUTCUnix() {
 					ve.Add("scheduleDate and Time", "must be greater than current time")
 				}
 				if helpers.Unix(endUnix) < helpers.CurrentUTCUnix() || startUnix > endUnix {
@@ -133,7 +307,130 @@ func (t *CreateRequest) Validate() error {
 	return ve.Err()
 }
 
-func (t *CreateRequest) ToTask(taskID, curTime string) (Task, error) {
+// validate checks the request definition. The url must be permitted by policy.
+func (d *Data) validate(ve *errors.ValidationErrorBuilder, policy *httpclient.Policy) {
+	helpers.ValidateRequiredString(ve, "taskData.taskType", d.TaskType)
+	if d.RequestType == "" {
+		ve.Add("taskData.requestType", "cannot be empty")
+	} else if err := d.RequestType.Validate(); err != nil {
+		ve.Add("taskData.requestType", err.Error())
+	}
+	helpers.ValidateRequiredString(ve, "taskData.url", d.URL)
+	if secrets.HasRefs(d.URL) {
+		ve.Add("taskData.url", "secret references are not allowed in url")
+	} else if d.URL != "" {
+		if err := policy.CheckURL(d.URL); err != nil {
+			ve.Add("taskData.url", err.Error())
+		}
+	}
+	for key, value := range d.Headers {
+		if err := secrets.ValidateRefs(value); err != nil {
+			ve.Add("taskData.headers."+key, err.Error())
+		}
+	}
+	d.validateQuery(ve, "taskData.")
+	d.validateBody(ve, "taskData.")
+}
+
+// ValidatePayload checks that the query params can be encoded and that the
+// body fields are consistent with the body type.
+func (d *Data) ValidatePayload() error {
+	ve := errors.ValidationErrs()
+	d.validateQuery(ve, "")
+	d.validateBody(ve, "")
+	return ve.Err()
+}
+
+func (d *Data) validateQuery(ve *errors.ValidationErrorBuilder, prefix string) {
+	enc := d.QueryEncoding
+	if enc != "" {
+		if err := enc.Validate(); err != nil {
+			ve.Add(prefix+"queryEncoding", err.Error())
+			enc = ""
+		}
+	}
+	for key, value := range d.QueryParams {
+		if err := httpclient.ValidateQueryParam(key, value, enc); err != nil {
+			ve.Add(prefix+"queryParams."+key, err.Error())
+		}
+	}
+	validateSecretRefs(ve, prefix+"queryParams", d.QueryParams)
+}
+
+func (d *Data) validateBody(ve *errors.ValidationErrorBuilder, prefix string) {
+	bodyType := d.BodyType
+	if bodyType == "" {
+		bodyType = httpclient.BodyJSON
+	} else if err := bodyType.Validate(); err != nil {
+		ve.Add(prefix+"bodyType", err.Error())
+		return
+	}
+
+	usesMap := bodyType == httpclient.BodyJSON || bodyType == httpclient.BodyForm || bodyType == httpclient.BodyMultipart
+	if !usesMap && len(d.RequestBody) > 0 {
+		ve.Add(prefix+"requestBody", fmt.Sprintf("not allowed with bodyType %s, use rawBody", bodyType))
+	}
+	if usesMap && d.RawBody != "" {
+		ve.Add(prefix+"rawBody", fmt.Sprintf("not allowed with bodyType %s, use requestBody", bodyType))
+	}
+	if bodyType != httpclient.BodyMultipart && len(d.Files) > 0 {
+		ve.Add(prefix+"files", "only allowed with bodyType multipart")
+	}
+
+	switch bodyType {
+	case httpclient.BodyJSON:
+		validateSecretRefs(ve, prefix+"requestBody", d.RequestBody)
+	case httpclient.BodyForm, httpclient.BodyMultipart:
+		for key, value := range d.RequestBody {
+			if _, err := httpclient.FormValues(value); err != nil {
+				ve.Add(prefix+"requestBody."+key, err.Error())
+			}
+		}
+		validateSecretRefs(ve, prefix+"requestBody", d.RequestBody)
+		for i, f := range d.Files {
+			field := fmt.Sprintf("%sfiles[%d]", prefix, i)
+			helpers.ValidateRequiredString(ve, field+".field", f.Field)
+			helpers.ValidateRequiredString(ve, field+".filename", f.Filename)
+			if _, err := base64.StdEncoding.DecodeString(f.Content); err != nil {
+				ve.Add(field+".content", "must be base64 encoded")
+			}
+		}
+	case httpclient.BodyRaw:
+		helpers.ValidateRequiredString(ve, prefix+"rawBody", d.RawBody)
+		if err := secrets.ValidateRefs(d.RawBody); err != nil {
+			ve.Add(prefix+"rawBody", err.Error())
+		}
+	case httpclient.BodyBinary:
+		helpers.ValidateRequiredString(ve, prefix+"rawBody", d.RawBody)
+		if _, err := base64.StdEncoding.DecodeString(d.RawBody); err != nil {
+			ve.Add(prefix+"rawBody", "must be base64 encoded")
+		}
+	}
+}
+
+// validateSecretRefs checks secret references in every string value of m, at any depth.
+func validateSecretRefs(ve *errors.ValidationErrorBuilder, field string, m map[string]any) {
+	for key, value := range m {
+		validateSecretRefValue(ve, field+"."+key, value)
+	}
+}
+
+func validateSecretRefValue(ve *errors.ValidationErrorBuilder, field string, value any) {
+	switch v := value.(type) {
+	case string:
+		if err := secrets.ValidateRefs(v); err != nil {
+			ve.Add(field, err.Error())
+		}
+	case map[string]any:
+		validateSecretRefs(ve, field, v)
+	case []any:
+		for i, item := range v {
+			validateSecretRefValue(ve, fmt.Sprintf("%s[%d]", field, i), item)
+		}
+	}
+}
+
+func (t *CreateRequest) ToTask(taskID, namespace, curTime string) (Task, error) {
 	startUnix, err := helpers.ToUnixFromISTDateTime(t.ScheduleTime, t.ScheduleDate)
 	if err != nil {
 		return Task{}, fmt.Errorf("toTask: %w", err)
@@ -144,6 +441,10 @@ func (t *CreateRequest) ToTask(taskID, curTime string) (Task, error) {
 	}
 	return Task{
 		ID:               taskID,
+		Namespace:        namespace,
+		ExternalID:       t.ExternalID,
+		RequestHash:      t.RequestHash,
+		Labels:           t.Labels,
 		Schedule:         t.Schedule,
 		Enable:           t.Enable,
 		ScheduleDate:     t.ScheduleDate,
@@ -151,6 +452,8 @@ func (t *CreateRequest) ToTask(taskID, curTime string) (Task, error) {
 		Recur:            t.Recur,
 		IsRecurEnabled:   t.IsRecurEnabled,
 		NumberOfAttempts: t.NumberOfAttempts,
+		RateLimit:        t.RateLimit,
+		Completion:       t.Completion,
 		CreatedAt:        curTime,
 		UpdatedAt:        curTime,
 		ExpiresAt:        t.ExpiresAt,
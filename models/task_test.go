@@ -0,0 +1,104 @@
+package models
+
+import (
+	// Go Internal Packages
+	"testing"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	helpers "scheduler/utils/helpers"
+	httpclient "scheduler/utils/httpclient"
+)
+
+func TestCreateRequestValidateStart(t *testing.T) {
+	policy, err := httpclient.NewPolicy(config.Outbound{AllowedSchemes: []string{"https"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := time.LoadLocation(helpers.ISTZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := func(start, expires time.Time) CreateRequest {
+		ist := start.In(loc)
+		return CreateRequest{
+			Enable:         true,
+			ScheduleDate:   ist.Format("2006-01-02"),
+			ScheduleTime:   ist.Format("15:04"),
+			Recur:          3600,
+			IsRecurEnabled: true,
+			ExpiresAt:      expires.UTC().Format("2006-01-02T15:04:05.999Z"),
+			TaskData:       Data{TaskType: "report", RequestType: "GET", URL: "https://api.example.com/run"},
+		}
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		req           CreateRequest
+		wantCreateErr bool
+		wantUpdateErr bool
+	}{
+		{"future start", request(now.Add(time.Hour), now.Add(48*time.Hour)), false, false},
+		{"past start", request(now.Add(-24*time.Hour), now.Add(48*time.Hour)), true, false},
+		{"expired", request(now.Add(-48*time.Hour), now.Add(-time.Hour)), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(TaskRules{Policy: policy}); (err != nil) != tt.wantCreateErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantCreateErr)
+			}
+			if err := tt.req.ValidateUpdate(TaskRules{Policy: policy}); (err != nil) != tt.wantUpdateErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantUpdateErr)
+			}
+		})
+	}
+}
+
+func TestCreateRequestValidateNilPolicy(t *testing.T) {
+	req := CreateRequest{
+		ScheduleDate: time.Now().Add(48 * time.Hour).Format("2006-01-02"),
+		ScheduleTime: "10:00",
+		TaskData:     Data{TaskType: "report", RequestType: "GET", URL: "http://10.0.0.1/run"},
+	}
+	req.Normalize()
+	if err := req.Validate(TaskRules{}); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestCreateRequestFingerprint(t *testing.T) {
+	base := func(edit func(r *CreateRequest)) string {
+		r := CreateRequest{
+			ExternalID:   "order-1",
+			ScheduleDate: "2026-01-01",
+			ScheduleTime: "10:00",
+			TaskData:     Data{TaskType: "report", RequestType: "GET", URL: "https://api.example.com/run"},
+		}
+		edit(&r)
+		r.Normalize()
+		return r.RequestHash
+	}
+	want := base(func(*CreateRequest) {})
+
+	tests := []struct {
+		name      string
+		edit      func(r *CreateRequest)
+		wantEqual bool
+	}{
+		{"same request", func(*CreateRequest) {}, true},
+		{"default spelled out", func(r *CreateRequest) { r.NumberOfAttempts = 3 }, true},
+		{"schedule case", func(r *CreateRequest) { r.Schedule = "now" }, true},
+		{"padded external id", func(r *CreateRequest) { r.ExternalID = " order-1 " }, true},
+		{"different url", func(r *CreateRequest) { r.TaskData.URL = "https://api.example.com/other" }, false},
+		{"explicit expiry", func(r *CreateRequest) { r.ExpiresAt = "2027-01-01T00:00:00Z" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base(tt.edit); (got == want) != tt.wantEqual {
+				t.Errorf("fingerprint equal = %v, want %v", got == want, tt.wantEqual)
+			}
+		})
+	}
+}
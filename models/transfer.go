@@ -0,0 +1,151 @@
+package models
+
+import (
+	// Go Internal Packages
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	// Local Packages
+	errors "scheduler/errors"
+	helpers "scheduler/utils/helpers"
+)
+
+// TransferFormat is the encoding of exported and imported task documents.
+type TransferFormat string
+
+const (
+	FormatJSONL TransferFormat = "jsonl" // one task document per line
+	FormatYAML  TransferFormat = "yaml"  // "---" separated task documents
+)
+
+func (f TransferFormat) Validate() error {
+	switch f {
+	case FormatJSONL, FormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid format %q, expected jsonl or yaml", string(f))
+	}
+}
+
+func (f TransferFormat) ContentType() string {
+	if f == FormatYAML {
+		return "application/yaml"
+	}
+	return "application/x-ndjson"
+}
+
+// ImportOptions controls how exported tasks are adapted to the target environment.
+type ImportOptions struct {
+	Mode         BatchMode
+	PreserveIDs  bool              // keep the exported _id instead of generating a new one
+	Enable       bool              // import tasks enabled; they are imported disabled by default
+	HostRewrites map[string]string // target url host (or hostname) → replacement
+}
+
+// ParseHostRewrite parses a "from=to" host rewrite rule.
+func ParseHostRewrite(rule string) (from, to string, err error) {
+	from, to, ok := strings.Cut(rule, "=")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || from == "" || to == "" {
+		return "", "", fmt.Errorf("invalid host rewrite %q, expected from=to", rule)
+	}
+	return from, to, nil
+}
+
+// PrepareImport applies the import options to an exported task and recomputes
+// its start and end times from the stored schedule fields. Call before ValidateImport.
+func (t *Task) PrepareImport(opts ImportOptions) {
+	t.Enable = opts.Enable
+	t.TaskData.URL = rewriteHost(t.TaskData.URL, opts.HostRewrites)
+	if startUnix, err := helpers.ToUnixFromISTDateTime(t.ScheduleTime, t.ScheduleDate); err == nil {
+		t.StartUnix = startUnix
+	}
+	if endUnix, err := helpers.ToUnixFromUTCTime(t.ExpiresAt); err == nil {
+		t.EndUnix = endUnix
+	}
+}
+
+// rewriteHost replaces the host of rawURL when it, or its hostname without
+// the port, has a rewrite rule. Unparseable urls are returned unchanged.
+func rewriteHost(rawURL string, rewrites map[string]string) string {
+	if len(rewrites) == 0 {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if to, ok := rewrites[u.Host]; ok {
+		u.Host = to
+	} else if to, ok := rewrites[u.Hostname()]; ok {
+		if port := u.Port(); port != "" && !strings.Contains(to, ":") {
+			to += ":" + port
+		}
+		u.Host = to
+	} else {
+		return rawURL
+	}
+	return u.String()
+}
+
+// ValidateImport checks an exported task. Unlike CreateRequest.Validate it
+// accepts start times in the past and a run status, since existing tasks are
+// being moved; the status must still be one the scheduler could have written.
+func (t *Task) ValidateImport(rules TaskRules, preserveID bool) error {
+	ve := errors.ValidationErrs()
+
+	if preserveID {
+		helpers.ValidateRequiredString(ve, "_id", t.ID)
+	}
+	if len(t.ExternalID) > maxExternalIDLen {
+		ve.Add("externalId", fmt.Sprintf("must be at most %d characters", maxExternalIDLen))
+	}
+	// Exported tasks keep the ownership labels of sync, so that sync still
+	// manages them once they are imported.
+	ValidateLabels(ve, "labels", t.Labels, true)
+	helpers.ValidateDate(ve, "scheduleDate", t.ScheduleDate)
+	helpers.ValidateTime(ve, "scheduleTime", t.ScheduleTime)
+	if _, err := time.Parse("2006-01-02T15:04:05.999Z", t.ExpiresAt); err != nil {
+		ve.Add("expiresAt", "Invalid format, expected RFC3339 NANO")
+	}
+	if t.Recur < 0 {
+		ve.Add("recur", "cannot be negative")
+	}
+	if !t.IsRecurEnabled && t.Recur != 0 {
+		ve.Add("recur", "needs to be 0 for non-recurring task")
+	}
+	if t.IsRecurEnabled && t.Recur < 3600 {
+		ve.Add("recur", "needs to be greater than 1hr if recur is enabled")
+	}
+	if t.NumberOfAttempts <= 0 {
+		ve.Add("numberOfAttempts", "must be greater than 0")
+	}
+	t.RateLimit.validate(ve, "rateLimit")
+	t.Completion.validate(ve, "completion", rules.Callbacks)
+	t.TaskData.validate(ve, rules.Policy)
+	t.Status.validateImport(ve)
+	if ve.Len() == 0 && t.StartUnix > t.EndUnix {
+		ve.Add("expiresAt", "must be greater than schedule time")
+	}
+
+	return ve.Err()
+}
+
+// validateImport checks the run status of an exported task.
+func (s *Status) validateImport(ve *errors.ValidationErrorBuilder) {
+	switch s.Outcome {
+	case "", OutcomeSucceeded, OutcomeFailed, OutcomeCancelled, OutcomeSkipped, OutcomeShortCircuited:
+	default:
+		ve.Add("status.outcome", fmt.Sprintf("unknown outcome %q", string(s.Outcome)))
+		return
+	}
+	if s.LastExecutedAt != "" {
+		if _, err := time.Parse("2006-01-02T15:04:05.999Z", s.LastExecutedAt); err != nil {
+			ve.Add("status.lastExecutedAt", "Invalid format, expected RFC3339 NANO")
+		}
+	} else if s.Outcome.Ran() || s.IsComplete {
+		ve.Add("status", "a task that never executed cannot have a completed run")
+	}
+}
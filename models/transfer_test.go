@@ -0,0 +1,46 @@
+package models
+
+import (
+	// Go Internal Packages
+	"testing"
+)
+
+func TestValidateImport(t *testing.T) {
+	task := func(edit func(t *Task)) Task {
+		t := Task{
+			ID:               "t1",
+			ScheduleDate:     "2026-01-01",
+			ScheduleTime:     "10:00",
+			ExpiresAt:        "2027-01-01T00:00:00Z",
+			Recur:            3600,
+			IsRecurEnabled:   true,
+			NumberOfAttempts: 3,
+			TaskData:         Data{TaskType: "report", RequestType: "GET", URL: "https://api.example.com/run"},
+			Status:           Status{LastExecutedAt: "2026-01-01T04:30:00Z", Outcome: OutcomeSucceeded},
+		}
+		edit(&t)
+		t.PrepareImport(ImportOptions{})
+		return t
+	}
+
+	tests := []struct {
+		name    string
+		task    Task
+		wantErr bool
+	}{
+		{"ran before", task(func(*Task) {}), false},
+		{"never ran", task(func(t *Task) { t.Status = Status{} }), false},
+		{"skipped", task(func(t *Task) { t.Status = Status{Outcome: OutcomeSkipped} }), false},
+		{"recur on one-time task", task(func(t *Task) { t.IsRecurEnabled = false }), true},
+		{"unknown outcome", task(func(t *Task) { t.Status.Outcome = "exploded" }), true},
+		{"bad lastExecutedAt", task(func(t *Task) { t.Status.LastExecutedAt = "yesterday" }), true},
+		{"run outcome without execution", task(func(t *Task) { t.Status.LastExecutedAt = "" }), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.task.ValidateImport(TaskRules{}, true); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
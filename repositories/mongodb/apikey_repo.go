@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	// Go Internal Packages
+	"context"
+
+	// Local Packages
+	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type APIKeyRepository struct {
+	client     *Client
+	database   string
+	collection string
+}
+
+func NewAPIKeyRepository(client *Client) *APIKeyRepository {
+	return &APIKeyRepository{
+		client:     client,
+		database:   "scheduler",
+		collection: "api_keys",
+	}
+}
+
+// EnsureIndexes creates the unique indexes on key hash and key name.
+func (r *APIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "keyHash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	return err
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"keyHash": keyHash, "revoked": false}
+	var result models.APIKey
+	err := collection.FindOne(ctx, filter).Decode(&result)
+	return result, err
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	result := []models.APIKey{}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *APIKeyRepository) Insert(ctx context.Context, key models.APIKey) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.InsertOne(ctx, key)
+	return err
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, keyID string) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": keyID}
+	res, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked": true}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID string) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": keyID}
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"lastUsedAt": helpers.GetCurrentDateTime()}})
+	return err
+}
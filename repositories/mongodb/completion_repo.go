@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	// Go Internal Packages
+	"context"
+
+	// Local Packages
+	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CompletionRepository keeps runs that wait for their result. Every way out
+// of the waiting state deletes the document atomically, so each run is
+// completed exactly once.
+type CompletionRepository struct {
+	client     *Client
+	database   string
+	collection string
+}
+
+func NewCompletionRepository(client *Client) *CompletionRepository {
+	return &CompletionRepository{
+		client:     client,
+		database:   "scheduler",
+		collection: "pending_completions",
+	}
+}
+
+func (r *CompletionRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deadline", Value: 1}}},
+		{Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "taskId", Value: 1}}},
+	})
+	return err
+}
+
+func (r *CompletionRepository) Insert(ctx context.Context, pc models.PendingCompletion) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.InsertOne(ctx, pc)
+	return err
+}
+
+// Delete removes a pending completion, reporting whether it was still pending.
+func (r *CompletionRepository) Delete(ctx context.Context, id string) (bool, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// Complete removes and returns the pending completion with the given ID and
+// token hash. A wrong token, or a run that was already completed, yields
+// mongo.ErrNoDocuments.
+func (r *CompletionRepository) Complete(ctx context.Context, id, tokenHash string) (models.PendingCompletion, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	var result models.PendingCompletion
+	err := collection.FindOneAndDelete(ctx, bson.M{"_id": id, "tokenHash": tokenHash}).Decode(&result)
+	return result, err
+}
+
+// ClaimExpired removes and returns one pending completion whose deadline has
+// passed, or mongo.ErrNoDocuments if there is none.
+func (r *CompletionRepository) ClaimExpired(ctx context.Context, curUnix helpers.Unix) (models.PendingCompletion, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	opts := options.FindOneAndDelete().SetSort(bson.D{{Key: "deadline", Value: 1}})
+	var result models.PendingCompletion
+	err := collection.FindOneAndDelete(ctx, bson.M{"deadline": bson.M{"$lte": curUnix}}, opts).Decode(&result)
+	return result, err
+}
+
+// List returns the namespace's waiting runs, earliest deadline first,
+// optionally only those of one task.
+func (r *CompletionRepository) List(ctx context.Context, namespace, taskID string) ([]models.PendingCompletion, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"namespace": namespace}
+	if taskID != "" {
+		filter["taskId"] = taskID
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "deadline", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	result := []models.PendingCompletion{}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
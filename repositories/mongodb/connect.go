@@ -21,6 +21,10 @@ func Connect(ctx context.Context, logger *zap.Logger, uri string) (*Client, erro
 	timeout := time.Second * 5
 	opts := options.Client().ApplyURI(uri).SetServerSelectionTimeout(timeout)
 
+	// Decode nested documents in free-form task data (headers, query params,
+	// request body) as plain maps rather than bson.D.
+	opts.SetBSONOptions(&options.BSONOptions{DefaultDocumentMap: true})
+
 	// Create a new MongoDB client with the provided URI and options.
 	client, err := mongo.Connect(opts)
 	if err != nil {
@@ -0,0 +1,97 @@
+package mongodb
+
+import (
+	// Go Internal Packages
+	"context"
+	"time"
+
+	// Local Packages
+	models "scheduler/models"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type DeadLetterRepository struct {
+	client     *Client
+	database   string
+	collection string
+	retention  time.Duration
+}
+
+func NewDeadLetterRepository(client *Client, retention time.Duration) *DeadLetterRepository {
+	return &DeadLetterRepository{
+		client:     client,
+		database:   "scheduler",
+		collection: "dead_letters",
+		retention:  retention,
+	}
+}
+
+// EnsureIndexes creates the listing index and the TTL index that removes dead
+// letters once their retention has passed.
+func (r *DeadLetterRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "taskId", Value: 1}, {Key: "failedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "expireAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// Insert stores a dead letter, to be removed after the retention period.
+func (r *DeadLetterRepository) Insert(ctx context.Context, dl models.DeadLetter) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	dl.ExpireAt = time.Now().Add(r.retention)
+	_, err := collection.InsertOne(ctx, dl)
+	return err
+}
+
+// List returns the namespace's dead letters, most recent first, optionally
+// only those of one task.
+func (r *DeadLetterRepository) List(ctx context.Context, namespace, taskID string, limit, skip int64) ([]models.DeadLetter, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"namespace": namespace}
+	if taskID != "" {
+		filter["taskId"] = taskID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "failedAt", Value: -1}, {Key: "_id", Value: 1}}).SetSkip(skip)
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	result := []models.DeadLetter{}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *DeadLetterRepository) GetOne(ctx context.Context, namespace, id string) (models.DeadLetter, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": id, "namespace": namespace}
+	var result models.DeadLetter
+	err := collection.FindOne(ctx, filter).Decode(&result)
+	return result, err
+}
+
+func (r *DeadLetterRepository) Delete(ctx context.Context, namespace, id string) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": id, "namespace": namespace}
+	res, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package mongodb
+
+import (
+	// Go Internal Packages
+	"context"
+
+	// Local Packages
+	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// JobRepository is the Mongo-backed execution queue shared by the scheduler
+// and its workers.
+type JobRepository struct {
+	client     *Client
+	database   string
+	collection string
+}
+
+func NewJobRepository(client *Client) *JobRepository {
+	return &JobRepository{
+		client:     client,
+		database:   "scheduler",
+		collection: "jobs",
+	}
+}
+
+// EnsureIndexes creates the index workers lease jobs by.
+func (r *JobRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "leaseUntil", Value: 1}, {Key: "enqueuedAt", Value: 1}},
+	})
+	return err
+}
+
+// Enqueue adds a job. Enqueueing a job ID that is already queued is a no-op.
+func (r *JobRepository) Enqueue(ctx context.Context, job models.Job) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.InsertOne(ctx, job)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// Lease hands the oldest visible job to the worker until leaseUntil. It
+// returns mongo.ErrNoDocuments when no job is visible.
+func (r *JobRepository) Lease(ctx context.Context, workerID string, curUnix helpers.Unix, leaseUntil int64) (models.Job, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"leaseUntil": bson.M{"$lte": curUnix}}
+	update := bson.M{
+		"$set": bson.M{"leasedBy": workerID, "leaseUntil": leaseUntil},
+		"$inc": bson.M{"deliveries": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "leaseUntil", Value: 1}, {Key: "enqueuedAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job models.Job
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	return job, err
+}
+
+// Heartbeat extends the worker's lease on the job, reporting whether the
+// worker still held it.
+func (r *JobRepository) Heartbeat(ctx context.Context, jobID, workerID string, leaseUntil int64) (bool, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": jobID, "leasedBy": workerID}
+	res, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"leaseUntil": leaseUntil}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// Complete removes a job the worker has finished.
+func (r *JobRepository) Complete(ctx context.Context, jobID, workerID string) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.DeleteOne(ctx, bson.M{"_id": jobID, "leasedBy": workerID})
+	return err
+}
@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	// Go Internal Packages
+	"context"
+	"time"
+
+	// Local Packages
+	errors "scheduler/errors"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RateLimitRepository keeps rate limit state shared by every replica. Each
+// limit is one document holding the theoretical arrival time (tat, in unix
+// milliseconds) of its next request.
+type RateLimitRepository struct {
+	client     *Client
+	database   string
+	collection string
+}
+
+func NewRateLimitRepository(client *Client) *RateLimitRepository {
+	return &RateLimitRepository{
+		client:     client,
+		database:   "scheduler",
+		collection: "rate_limits",
+	}
+}
+
+// EnsureIndexes creates the TTL index that removes limits whose bucket has refilled.
+func (r *RateLimitRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expireAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// Reserve books the next request slot of key in a single atomic update, so
+// concurrent reservations from any number of replicas never share a slot.
+func (r *RateLimitRepository) Reserve(ctx context.Context, key string, interval time.Duration, burst int, maxWait time.Duration) (time.Duration, bool, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	now := time.Now().UnixMilli()
+	step := interval.Milliseconds()
+	tolerance := int64(burst-1) * step
+	// Clamp so that an unbounded maxWait cannot overflow the comparison.
+	waitLimit := min(maxWait.Milliseconds(), (24 * time.Hour).Milliseconds())
+	latest := now + tolerance + waitLimit
+
+	tat := bson.M{"$ifNull": bson.A{"$tat", int64(0)}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tat": bson.M{"$cond": bson.A{
+			bson.M{"$lte": bson.A{tat, latest}},
+			bson.M{"$add": bson.A{bson.M{"$max": bson.A{tat, now}}, step}},
+			tat,
+		}}}}},
+		{{Key: "$set", Value: bson.M{"expireAt": bson.M{"$toDate": "$tat"}}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+
+	var before struct {
+		TAT int64 `bson:"tat"`
+	}
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, true, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	wait := max(before.TAT-tolerance-now, 0)
+	return time.Duration(wait) * time.Millisecond, wait <= waitLimit, nil
+}
+
+// Refund moves the next request slot of key back by one interval. A limit
+// that no longer exists has refilled already and is left alone.
+func (r *RateLimitRepository) Refund(ctx context.Context, key string, interval time.Duration) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tat": bson.M{"$subtract": bson.A{"$tat", interval.Milliseconds()}}}}},
+		{{Key: "$set", Value: bson.M{"expireAt": bson.M{"$toDate": "$tat"}}}},
+	}
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": key}, update)
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	// External Packages
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type SchedulerRepository struct {
@@ -27,15 +28,55 @@ func NewSchedulerRepository(client *Client) *SchedulerRepository {
 	}
 }
 
-func (r *SchedulerRepository) GetOne(ctx context.Context, taskID string) (models.Task, error) {
+// EnsureIndexes assigns tasks created before namespaces existed to the
+// default namespace and creates the namespace, external ID and dispatch indexes.
+func (r *SchedulerRepository) EnsureIndexes(ctx context.Context) error {
 	collection := r.client.Database(r.database).Collection(r.collection)
-	filter := bson.M{"_id": taskID}
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"namespace": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"namespace": models.DefaultNamespace}},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "enable", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "namespace", Value: 1}, {Key: "externalId", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"externalId": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "nextRunAt", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"nextRunAt": bson.M{"$gt": 0}}),
+		},
+		{
+			Keys:    bson.D{{Key: "claim.heartbeatAt", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"claim": bson.M{"$exists": true}}),
+		},
+	})
+	return err
+}
+
+func (r *SchedulerRepository) GetOne(ctx context.Context, namespace, taskID string) (models.Task, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": taskID, "namespace": namespace}
 	var result models.Task
 	err := collection.FindOne(ctx, filter).Decode(&result)
 	return result, err
 }
 
-func (r *SchedulerRepository) GetActive(ctx context.Context, curUnix helpers.Unix) ([]models.Task, error) {
+func (r *SchedulerRepository) GetByExternalID(ctx context.Context, namespace, externalID string) (models.Task, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"externalId": externalID, "namespace": namespace}
+	var result models.Task
+	err := collection.FindOne(ctx, filter).Decode(&result)
+	return result, err
+}
+
+// GetActive returns enabled, unexpired tasks that still have runs left.
+// An empty namespace matches tasks in every namespace.
+func (r *SchedulerRepository) GetActive(ctx context.Context, namespace string, curUnix helpers.Unix) ([]models.Task, error) {
 	collection := r.client.Database(r.database).Collection(r.collection)
 	filter := bson.M{
 		"enable":  true,
@@ -45,6 +86,9 @@ func (r *SchedulerRepository) GetActive(ctx context.Context, curUnix helpers.Uni
 			{"status.lastExecutedAt": ""},
 		},
 	}
+	if namespace != "" {
+		filter["namespace"] = namespace
+	}
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -65,17 +109,86 @@ func (r *SchedulerRepository) GetActive(ctx context.Context, curUnix helpers.Uni
 	return result, nil
 }
 
+// List returns tasks in the namespace matching the label selector, oldest first.
+// A limit of zero returns every match.
+func (r *SchedulerRepository) List(ctx context.Context, namespace string, selector models.Selector, limit, skip int64) ([]models.Task, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := selectorFilter(selector)
+	filter["namespace"] = namespace
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetSkip(skip)
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	result := []models.Task{}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (r *SchedulerRepository) Insert(ctx context.Context, task models.Task) error {
 	collection := r.client.Database(r.database).Collection(r.collection)
 	_, err := collection.InsertOne(ctx, task)
 	return err
 }
 
-func (r *SchedulerRepository) UpdateEnable(ctx context.Context, taskID string, enable bool) (bool, error) {
+func (r *SchedulerRepository) Count(ctx context.Context, namespace string) (int64, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	return collection.CountDocuments(ctx, bson.M{"namespace": namespace})
+}
+
+// InsertMany inserts tasks in a single bulk write. When ordered is false every
+// task is attempted and failures are reported per index in a mongo.BulkWriteException.
+func (r *SchedulerRepository) InsertMany(ctx context.Context, tasks []models.Task, ordered bool) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	docs := make([]any, len(tasks))
+	for i := range tasks {
+		docs[i] = tasks[i]
+	}
+	_, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(ordered))
+	return err
+}
+
+// Replace overwrites the stored task with the same ID and namespace.
+func (r *SchedulerRepository) Replace(ctx context.Context, task models.Task) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": task.ID, "namespace": task.Namespace}
+	res, err := collection.ReplaceOne(ctx, filter, task)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *SchedulerRepository) DeleteMany(ctx context.Context, namespace string, taskIDs []string) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": bson.M{"$in": taskIDs}, "namespace": namespace}
+	_, err := collection.DeleteMany(ctx, filter)
+	return err
+}
+
+// UpdateEnable flips the enable flag and sets the task's next run time,
+// reporting whether the task was in the opposite state.
+func (r *SchedulerRepository) UpdateEnable(ctx context.Context, namespace, taskID string, enable bool, nextRunAt int64, updatedBy string) (bool, error) {
 	collection := r.client.Database(r.database).Collection(r.collection)
-	filter := bson.M{"_id": taskID, "enable": !enable}
+	filter := bson.M{"_id": taskID, "namespace": namespace, "enable": !enable}
 	currTime := helpers.GetCurrentDateTime()
-	updatedFields := bson.M{"$set": bson.M{"enable": enable, "updatedAt": currTime}}
+	updatedFields := bson.M{"$set": bson.M{
+		"enable":    enable,
+		"nextRunAt": nextRunAt,
+		"updatedAt": currTime,
+		"updatedBy": updatedBy,
+	}}
 	res, err := collection.UpdateOne(ctx, filter, updatedFields)
 	if err != nil {
 		return false, err
@@ -83,9 +196,9 @@ func (r *SchedulerRepository) UpdateEnable(ctx context.Context, taskID string, e
 	return res.MatchedCount > 0, nil
 }
 
-func (r *SchedulerRepository) Delete(ctx context.Context, taskID string) error {
+func (r *SchedulerRepository) Delete(ctx context.Context, namespace, taskID string) error {
 	collection := r.client.Database(r.database).Collection(r.collection)
-	filter := bson.M{"_id": taskID}
+	filter := bson.M{"_id": taskID, "namespace": namespace}
 	res, err := collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
@@ -96,16 +209,153 @@ func (r *SchedulerRepository) Delete(ctx context.Context, taskID string) error {
 	return nil
 }
 
-func (r *SchedulerRepository) UpdateTaskStatus(ctx context.Context, taskID, exceptionMsg string, isComplete bool) error {
+// UpdateTaskStatus records the outcome of a run. Only a successful run marks the task complete,
+// and only a run that started sets lastExecutedAt, so a skipped one-off task is still pending.
+func (r *SchedulerRepository) UpdateTaskStatus(ctx context.Context, taskID string, outcome models.Outcome, exceptionMsg string) error {
 	collection := r.client.Database(r.database).Collection(r.collection)
 	filter := bson.M{"_id": taskID}
-	updateData := bson.M{
-		"$set": bson.M{
-			"status.lastExecutedAt":   helpers.GetCurrentDateTime(),
-			"status.isComplete":       isComplete,
-			"status.exceptionMessage": exceptionMsg,
-		},
+	set := bson.M{
+		"status.isComplete":       outcome == models.OutcomeSucceeded,
+		"status.outcome":          outcome,
+		"status.exceptionMessage": exceptionMsg,
+	}
+	if outcome.Ran() {
+		set["status.lastExecutedAt"] = helpers.GetCurrentDateTime()
+	}
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	return err
+}
+
+// RetrySkipped sets the next run of a non-recurring task whose fire was
+// skipped. A task that was rescheduled, disabled or has run since is left as is.
+func (r *SchedulerRepository) RetrySkipped(ctx context.Context, taskID string, nextRunAt int64) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{
+		"_id":                   taskID,
+		"nextRunAt":             0,
+		"enable":                true,
+		"isRecurEnabled":        false,
+		"status.lastExecutedAt": "",
 	}
-	_, err := collection.UpdateOne(ctx, filter, updateData)
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"nextRunAt": nextRunAt}})
 	return err
 }
+
+// GetDue returns up to limit enabled tasks whose next run is at or before
+// curUnix, earliest first.
+func (r *SchedulerRepository) GetDue(ctx context.Context, curUnix helpers.Unix, limit int64) ([]models.Task, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"nextRunAt": bson.M{"$gt": 0, "$lte": curUnix}, "enable": true}
+	opts := options.Find().SetSort(bson.D{{Key: "nextRunAt", Value: 1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	result := []models.Task{}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClaimRun moves the task's next run from due to next if the task is still
+// due at that time and unchanged since updatedAt, reporting whether it did.
+// Exactly one caller wins the claim of a given fire. The claim record is set
+// along, or removed when claim is nil.
+func (r *SchedulerRepository) ClaimRun(ctx context.Context, taskID string, due, next int64, updatedAt string, claim *models.FireClaim) (bool, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": taskID, "nextRunAt": due, "updatedAt": updatedAt, "enable": true}
+	update := bson.M{"$set": bson.M{"nextRunAt": next}, "$unset": bson.M{"claim": ""}}
+	if claim != nil {
+		update = bson.M{"$set": bson.M{"nextRunAt": next, "claim": claim}}
+	}
+	res, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// ReleaseClaim removes the claim of the given fire once its run has ended.
+func (r *SchedulerRepository) ReleaseClaim(ctx context.Context, taskID string, fireAt int64) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": taskID, "claim.fireAt": fireAt}
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"claim": ""}})
+	return err
+}
+
+// HeartbeatClaims refreshes every claim held by the instance.
+func (r *SchedulerRepository) HeartbeatClaims(ctx context.Context, instance string, curUnix helpers.Unix) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"claim.instance": instance}
+	_, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"claim.heartbeatAt": curUnix}})
+	return err
+}
+
+// RecoverClaims gives back the fires of claims last refreshed before
+// staleBefore: their next run is set to the claimed fire, which is due, and
+// the claim is removed. It returns how many fires it gave back.
+func (r *SchedulerRepository) RecoverClaims(ctx context.Context, staleBefore helpers.Unix) (int64, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"claim.heartbeatAt": bson.M{"$lt": staleBefore}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"nextRunAt": "$claim.fireAt"}}},
+		{{Key: "$unset", Value: "claim"}},
+	}
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// GetUnscheduled returns up to limit tasks stored before next run times were
+// persisted, which have no nextRunAt field.
+func (r *SchedulerRepository) GetUnscheduled(ctx context.Context, limit int64) ([]models.Task, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"nextRunAt": bson.M{"$exists": false}}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	result := []models.Task{}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// InitNextRunAt sets the next run time of a task that has none yet.
+func (r *SchedulerRepository) InitNextRunAt(ctx context.Context, taskID string, nextRunAt int64) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": taskID, "nextRunAt": bson.M{"$exists": false}}
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"nextRunAt": nextRunAt}})
+	return err
+}
+
+// selectorFilter translates a label selector into a query on the labels field.
+func selectorFilter(selector models.Selector) bson.M {
+	filter := bson.M{}
+	var and []bson.M
+	for _, req := range selector {
+		field := "labels." + req.Key
+		switch req.Op {
+		case models.SelectorEquals:
+			and = append(and, bson.M{field: req.Value})
+		case models.SelectorNotEquals:
+			and = append(and, bson.M{field: bson.M{"$ne": req.Value}})
+		case models.SelectorExists:
+			and = append(and, bson.M{field: bson.M{"$exists": true}})
+		case models.SelectorNotExists:
+			and = append(and, bson.M{field: bson.M{"$exists": false}})
+		}
+	}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	return filter
+}
@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"time"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type UsageRepository struct {
+	client     *Client
+	database   string
+	collection string
+}
+
+func NewUsageRepository(client *Client) *UsageRepository {
+	return &UsageRepository{
+		client:     client,
+		database:   "scheduler",
+		collection: "namespace_usage",
+	}
+}
+
+// EnsureIndexes creates the TTL index that removes usage counters once their window has passed.
+func (r *UsageRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expireAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// IncrementExecutions adds one execution to the namespace's counter for the
+// hour containing now and returns the new count.
+func (r *UsageRepository) IncrementExecutions(ctx context.Context, namespace string, now time.Time) (int64, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	hour := now.UTC().Truncate(time.Hour)
+	filter := bson.M{"_id": fmt.Sprintf("%s:%d", namespace, hour.Unix())}
+	update := bson.M{
+		"$inc":         bson.M{"executions": 1},
+		"$setOnInsert": bson.M{"namespace": namespace, "hour": hour, "expireAt": hour.Add(2 * time.Hour)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result struct {
+		Executions int64 `bson:"executions"`
+	}
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.Executions, nil
+}
+
+// DecrementExecutions takes back one execution from the namespace's counter
+// for the hour containing now.
+func (r *UsageRepository) DecrementExecutions(ctx context.Context, namespace string, now time.Time) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	hour := now.UTC().Truncate(time.Hour)
+	filter := bson.M{"_id": fmt.Sprintf("%s:%d", namespace, hour.Unix()), "executions": bson.M{"$gt": 0}}
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"executions": -1}})
+	return err
+}
+
+// LockTasks takes the namespace's task lock for token until until. It reports
+// false when another holder's lock has not yet expired. An abandoned lock is
+// taken over once it expires and is removed by the TTL index afterwards.
+func (r *UsageRepository) LockTasks(ctx context.Context, namespace, token string, now, until time.Time) (bool, error) {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	filter := bson.M{"_id": "tasks-lock:" + namespace, "expireAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"namespace": namespace, "token": token, "expireAt": until}}
+	_, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// The lock exists and is still held.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnlockTasks releases the namespace's task lock if token still holds it.
+func (r *UsageRepository) UnlockTasks(ctx context.Context, namespace, token string) error {
+	collection := r.client.Database(r.database).Collection(r.collection)
+	_, err := collection.DeleteOne(ctx, bson.M{"_id": "tasks-lock:" + namespace, "token": token})
+	return err
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	// Go Internal Packages
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
+
+	// External Packages
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	keyPrefix       = "sk_"
+	displayedPrefix = 10
+	lastUsedEvery   = time.Minute
+)
+
+type APIKeyRepo interface {
+	GetByHash(ctx context.Context, keyHash string) (models.APIKey, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Insert(ctx context.Context, key models.APIKey) error
+	Revoke(ctx context.Context, keyID string) error
+	TouchLastUsed(ctx context.Context, keyID string) error
+}
+
+type AuthService struct {
+	logger        *zap.Logger
+	apiKeyRepo    APIKeyRepo
+	enabled       bool
+	bootstrapHash string
+	tokens        *TokenVerifier
+}
+
+func NewService(logger *zap.Logger, apiKeyRepo APIKeyRepo, cfg config.Auth) *AuthService {
+	s := &AuthService{
+		logger:     logger,
+		apiKeyRepo: apiKeyRepo,
+		enabled:    cfg.Enabled,
+	}
+	if cfg.BootstrapKey != "" {
+		s.bootstrapHash = helpers.SHA256(cfg.BootstrapKey)
+	}
+	if len(cfg.OIDC.Issuers) > 0 {
+		s.tokens = NewTokenVerifier(cfg.OIDC)
+	}
+	return s
+}
+
+// Authenticate resolves a raw API key to its principal. When authentication is
+// disabled every caller is treated as an anonymous admin.
+func (s *AuthService) Authenticate(ctx context.Context, rawKey string) (*models.Principal, error) {
+	if !s.enabled {
+		return &models.Principal{Subject: "anonymous", Kind: "anonymous", Scopes: []models.Scope{models.ScopeAdmin}}, nil
+	}
+	if rawKey == "" {
+		return nil, errors.NewError(errors.Unauthorized, "missing api key")
+	}
+
+	keyHash := helpers.SHA256(rawKey)
+	if s.bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(keyHash), []byte(s.bootstrapHash)) == 1 {
+		return &models.Principal{Subject: "bootstrap", Kind: "bootstrap", Scopes: []models.Scope{models.ScopeAdmin}}, nil
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, keyHash)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.NewError(errors.Unauthorized, "invalid api key")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api key: %w", err)
+	}
+
+	s.touchLastUsed(ctx, key)
+	return &models.Principal{Subject: key.Name, Kind: "api_key", Scopes: key.Scopes, Namespaces: key.Namespaces}, nil
+}
+
+// AuthenticateToken resolves a bearer JWT from a configured OIDC issuer to its principal.
+func (s *AuthService) AuthenticateToken(ctx context.Context, token string) (*models.Principal, error) {
+	if !s.enabled {
+		return s.Authenticate(ctx, "")
+	}
+	if s.tokens == nil {
+		return nil, errors.NewError(errors.Unauthorized, "bearer tokens are not accepted")
+	}
+
+	principal, err := s.tokens.Verify(ctx, token)
+	if err != nil {
+		s.logger.Debug("Bearer Token Rejected", zap.Error(err))
+		return nil, errors.NewError(errors.Unauthorized, "invalid bearer token", err)
+	}
+	return principal, nil
+}
+
+func (s *AuthService) CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	rawKey, err := generateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key := models.APIKey{
+		ID:         uuid.New().String(),
+		Name:       req.Name,
+		Prefix:     rawKey[:displayedPrefix],
+		KeyHash:    helpers.SHA256(rawKey),
+		Scopes:     req.Scopes,
+		Namespaces: req.Namespaces,
+		CreatedBy:  Subject(ctx),
+		CreatedAt:  helpers.GetCurrentDateTime(),
+	}
+	err = s.apiKeyRepo.Insert(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, errors.NewError(errors.Conflict, "api key with given name already exists")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert api key: %w", err)
+	}
+	return &models.CreatedAPIKey{APIKey: key, Key: rawKey}, nil
+}
+
+func (s *AuthService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys, err := s.apiKeyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *AuthService) RevokeKey(ctx context.Context, keyID string) error {
+	err := s.apiKeyRepo.Revoke(ctx, keyID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.NewError(errors.NotFound, "api key not found with given id")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// touchLastUsed records key usage at most once per lastUsedEvery to avoid a
+// write on every request.
+func (s *AuthService) touchLastUsed(ctx context.Context, key models.APIKey) {
+	if key.LastUsedAt != "" {
+		lastUsed, err := time.Parse("2006-01-02T15:04:05.999Z", key.LastUsedAt)
+		if err == nil && time.Since(lastUsed) < lastUsedEvery {
+			return
+		}
+	}
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID); err != nil {
+		s.logger.Warn("Failed To Update API Key Last Used", zap.String("keyId", key.ID), zap.Error(err))
+	}
+}
+
+// generateKey returns a new random key with 256 bits of entropy.
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	// Go Internal Packages
+	"context"
+
+	// Local Packages
+	models "scheduler/models"
+)
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated principal.
+func NewContext(ctx context.Context, p *models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*models.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*models.Principal)
+	return p, ok
+}
+
+// Subject returns the subject of the principal stored in ctx, or "" if none.
+func Subject(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	// Go Internal Packages
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+
+	// External Packages
+	"golang.org/x/sync/singleflight"
+)
+
+// minRefreshInterval bounds how often an unknown key id can trigger a refetch.
+const minRefreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet caches the signing keys of one issuer, loaded from a JWKS URL
+// (discovered from the issuer if not configured) or from a local file.
+type keySet struct {
+	issuer config.Issuer
+	ttl    time.Duration
+	client *http.Client
+	group  singleflight.Group // one refresh at a time, shared by every waiting request
+
+	mu        sync.Mutex // guards the fields below
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	retryAt   time.Time // no refresh is attempted before, after a failed one
+}
+
+func newKeySet(issuer config.Issuer, ttl time.Duration, client *http.Client) *keySet {
+	return &keySet{issuer: issuer, ttl: ttl, client: client}
+}
+
+// Get returns the key with the given id, refreshing the cache when it is
+// stale or the key id is unknown. The refresh runs without holding the lock,
+// and after a failed one the next is delayed by minRefreshInterval.
+func (k *keySet) Get(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	age := time.Since(k.fetchedAt)
+	key, ok := k.keys[kid]
+	known := k.keys != nil
+	backoff := time.Now().Before(k.retryAt)
+	k.mu.Unlock()
+
+	switch {
+	case ok && (age < k.ttl || backoff):
+		// Serve a stale key rather than failing every request while the IdP is unreachable.
+		return key, nil
+	case !ok && known && age < minRefreshInterval:
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	case backoff:
+		return nil, fmt.Errorf("signing keys unavailable, last refresh failed")
+	}
+
+	v, err, _ := k.group.Do("", func() (any, error) {
+		// The refresh is shared, so it must not end with the request that started it.
+		keys, err := k.load(context.WithoutCancel(ctx))
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		if err != nil {
+			k.retryAt = time.Now().Add(minRefreshInterval)
+			return nil, err
+		}
+		k.keys = keys
+		k.fetchedAt = time.Now()
+		k.retryAt = time.Time{}
+		return keys, nil
+	})
+	if err != nil {
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	if key, ok = v.(map[string]crypto.PublicKey)[kid]; !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (k *keySet) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var raw []byte
+	var err error
+	if k.issuer.JWKSFile != "" {
+		raw, err = os.ReadFile(k.issuer.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwks file: %w", err)
+		}
+	} else {
+		jwksURL := k.issuer.JWKSURL
+		if jwksURL == "" {
+			if jwksURL, err = k.discover(ctx); err != nil {
+				return nil, err
+			}
+		}
+		if raw, err = k.fetch(ctx, jwksURL); err != nil {
+			return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+		}
+	}
+	return parseJWKS(raw)
+}
+
+// discover reads jwks_uri from the issuer's OpenID configuration document.
+func (k *keySet) discover(ctx context.Context) (string, error) {
+	raw, err := k.fetch(ctx, strings.TrimSuffix(k.issuer.Issuer, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch openid configuration: %w", err)
+	}
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err = json.Unmarshal(raw, &doc); err != nil {
+		return "", fmt.Errorf("failed to decode openid configuration: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return "", fmt.Errorf("openid configuration has no jwks_uri")
+	}
+	return doc.JWKSURI, nil
+}
+
+func (k *keySet) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("non-2xx status: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		pub, err := key.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", key.Kid, err)
+		}
+		if pub != nil {
+			keys[key.Kid] = pub
+		}
+	}
+	return keys, nil
+}
+
+// publicKey converts the JWK to a Go public key. Unsupported key types are
+// skipped by returning a nil key.
+func (j jwk) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, fmt.Errorf("coordinates too large for %s", j.Crv)
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		new(big.Int).SetBytes(x).FillBytes(point[1 : 1+size])
+		new(big.Int).SetBytes(y).FillBytes(point[1+size:])
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	// Go Internal Packages
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+)
+
+const testJWKS = `{"keys":[{"kty":"EC","kid":"k1","crv":"P-256",
+"x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}]}`
+
+func TestKeySetGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		kid       string
+		wantErr   bool
+		wantCalls int32
+	}{
+		{"known key fetched once", http.StatusOK, "k1", false, 1},
+		{"unknown key refetched at most once a minute", http.StatusOK, "k2", true, 1},
+		{"failed refresh backs off", http.StatusInternalServerError, "k1", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls.Add(1)
+				time.Sleep(20 * time.Millisecond)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(testJWKS))
+			}))
+			defer srv.Close()
+
+			ks := newKeySet(config.Issuer{JWKSURL: srv.URL}, time.Hour, srv.Client())
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Go(func() {
+					_, err := ks.Get(context.Background(), tt.kid)
+					if (err != nil) != tt.wantErr {
+						t.Errorf("Get(%q) error = %v, wantErr %v", tt.kid, err, tt.wantErr)
+					}
+				})
+			}
+			wg.Wait()
+			// A later request within the back-off or refresh interval does not refetch.
+			_, _ = ks.Get(context.Background(), tt.kid)
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("jwks fetched %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestKeySetServesStaleKeyWhenRefreshFails(t *testing.T) {
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(testJWKS))
+	}))
+	defer srv.Close()
+
+	ks := newKeySet(config.Issuer{JWKSURL: srv.URL}, time.Hour, srv.Client())
+	if _, err := ks.Get(context.Background(), "k1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	ks.fetchedAt = time.Now().Add(-2 * time.Hour)
+	fail.Store(true)
+	if _, err := ks.Get(context.Background(), "k1"); err != nil {
+		t.Errorf("Get() with a stale key and a failing refresh error = %v", err)
+	}
+	if ks.retryAt.IsZero() {
+		t.Error("failed refresh did not set a retry time")
+	}
+}
@@ -0,0 +1,249 @@
+package auth
+
+import (
+	// Go Internal Packages
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	models "scheduler/models"
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type issuerVerifier struct {
+	config config.Issuer
+	keys   *keySet
+}
+
+// TokenVerifier validates bearer JWTs issued by the configured OIDC issuers
+// and maps their role claims to scopes.
+type TokenVerifier struct {
+	issuers    map[string]*issuerVerifier
+	leeway     time.Duration
+	roleScopes map[string][]models.Scope
+}
+
+func NewTokenVerifier(cfg config.OIDC) *TokenVerifier {
+	client := &http.Client{Timeout: 5 * time.Second}
+	v := &TokenVerifier{
+		issuers:    make(map[string]*issuerVerifier, len(cfg.Issuers)),
+		leeway:     cfg.Leeway,
+		roleScopes: make(map[string][]models.Scope, len(cfg.RoleScopes)),
+	}
+	for _, iss := range cfg.Issuers {
+		v.issuers[iss.Issuer] = &issuerVerifier{config: iss, keys: newKeySet(iss, cfg.JWKSCacheTTL, client)}
+	}
+	for role, scopes := range cfg.RoleScopes {
+		for _, scope := range scopes {
+			v.roleScopes[role] = append(v.roleScopes[role], models.Scope(scope))
+		}
+	}
+	return v
+}
+
+// Verify checks the token's signature and standard claims and returns the principal it describes.
+func (v *TokenVerifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	iss, _ := claims["iss"].(string)
+	issuer, ok := v.issuers[iss]
+	if !ok {
+		return nil, fmt.Errorf("untrusted issuer %q", iss)
+	}
+
+	key, err := issuer.keys.Get(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err = verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	if err = v.validateClaims(issuer.config, claims); err != nil {
+		return nil, err
+	}
+	return v.principal(issuer.config, claims)
+}
+
+func (v *TokenVerifier) validateClaims(issuer config.Issuer, claims map[string]any) error {
+	now := time.Now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return fmt.Errorf("token has no exp claim")
+	}
+	if now.After(exp.Add(v.leeway)) {
+		return fmt.Errorf("token expired")
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return fmt.Errorf("token not yet valid")
+	}
+	if !slices.Contains(stringsClaim(claims["aud"]), issuer.Audience) {
+		return fmt.Errorf("token audience does not include %q", issuer.Audience)
+	}
+	return nil
+}
+
+func (v *TokenVerifier) principal(issuer config.Issuer, claims map[string]any) (*models.Principal, error) {
+	subjectClaim := issuer.SubjectClaim
+	if subjectClaim == "" {
+		subjectClaim = "sub"
+	}
+	subject, _ := lookupClaim(claims, subjectClaim).(string)
+	if subject == "" {
+		return nil, fmt.Errorf("token has no %s claim", subjectClaim)
+	}
+
+	rolesClaim := issuer.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = "roles"
+	}
+	roles := stringsClaim(lookupClaim(claims, rolesClaim))
+
+	var scopes []models.Scope
+	for _, role := range roles {
+		for _, scope := range v.roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	var namespaces []string
+	if issuer.NamespacesClaim != "" {
+		namespaces = stringsClaim(lookupClaim(claims, issuer.NamespacesClaim))
+		if len(namespaces) == 0 {
+			// A token that should be namespace-scoped but lists none grants no access,
+			// rather than falling back to all namespaces.
+			return nil, fmt.Errorf("token has no %s claim", issuer.NamespacesClaim)
+		}
+	}
+
+	return &models.Principal{Subject: subject, Kind: "jwt", Roles: roles, Scopes: scopes, Namespaces: namespaces}, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256", "PS256":
+		hash = crypto.SHA256
+	case "RS384", "ES384", "PS384":
+		hash = crypto.SHA384
+	case "RS512", "ES512", "PS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+		case "PS":
+			err = rsa.VerifyPSS(pub, hash, digest, sig, nil)
+		default:
+			return fmt.Errorf("algorithm %q does not match rsa key", alg)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid token signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || len(sig) != 2*size {
+			return fmt.Errorf("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("invalid token signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type")
+	}
+}
+
+func decodeSegment(seg string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// lookupClaim resolves a dotted path such as "realm_access.roles".
+func lookupClaim(claims map[string]any, path string) any {
+	var cur any = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+func numericClaim(claims map[string]any, name string) (time.Time, bool) {
+	v, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+// stringsClaim accepts a single string, a list of strings or a space
+// separated string (as used by the "scope" claim).
+func stringsClaim(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return strings.Fields(val)
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+	notifications "scheduler/utils/notifications"
+
+	// External Packages
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen is reported when the circuit of a task's target host is
+// open. The run is recorded as short-circuited without sending a request.
+var ErrCircuitOpen = errors.New("circuit open for target host")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type attemptResult int
+
+const (
+	attemptSucceeded attemptResult = iota
+	attemptFailed
+	// attemptIgnored says nothing about the host, e.g. the run was cancelled.
+	attemptIgnored
+)
+
+// Breaker keeps a circuit per target host so that runs against a host that is
+// down stop early instead of each exhausting its retries and alerting. One
+// aggregated alert is sent when a circuit opens and one when it closes again.
+type Breaker struct {
+	cfg    config.Breaker
+	logger *zap.Logger
+	alerts notifications.Sender
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state circuitState
+	// gen changes with every state change, so results of attempts admitted
+	// under an earlier state are ignored.
+	gen            int
+	failures       int       // consecutive, while closed
+	openedAt       time.Time // start of the current open period
+	downSince      time.Time // first opening since the circuit was last closed
+	probes         int       // attempts in flight while half-open
+	shortCircuited int
+}
+
+func NewBreaker(cfg config.Breaker, logger *zap.Logger, alerts notifications.Sender) *Breaker {
+	return &Breaker{
+		cfg:      cfg,
+		logger:   logger,
+		alerts:   alerts,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// Allow reports whether an attempt against host may be sent. If so, the
+// returned function must be called with the attempt's result.
+func (b *Breaker) Allow(host string) (done func(attemptResult), err error) {
+	if !b.cfg.Enabled {
+		return func(attemptResult) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+
+	if c.state == circuitOpen && time.Since(c.openedAt) >= b.cfg.OpenTimeout {
+		c.state = circuitHalfOpen
+		c.gen++
+		c.probes = 0
+		b.logger.Info("Circuit Half-Open, Probing Target Host", zap.String("host", host))
+	}
+	switch c.state {
+	case circuitOpen:
+		c.shortCircuited++
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	case circuitHalfOpen:
+		if c.probes >= b.cfg.HalfOpenProbes {
+			c.shortCircuited++
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+		c.probes++
+	}
+
+	gen := c.gen
+	var once sync.Once
+	return func(result attemptResult) {
+		once.Do(func() { b.record(host, gen, result) })
+	}, nil
+}
+
+func (b *Breaker) record(host string, gen int, result attemptResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host]
+	if !ok || c.gen != gen {
+		return
+	}
+	if c.state == circuitHalfOpen {
+		c.probes--
+	}
+	if result == attemptIgnored {
+		return
+	}
+
+	switch c.state {
+	case circuitClosed:
+		if result == attemptSucceeded {
+			// A healthy host needs no state.
+			delete(b.circuits, host)
+			return
+		}
+		c.failures++
+		if c.failures >= b.cfg.FailureThreshold {
+			c.downSince = time.Now()
+			b.open(c)
+			b.logger.Warn("Circuit Opened For Target Host", zap.String("host", host), zap.Int("failures", c.failures))
+			b.notify("Circuit Opened For Target Host", fmt.Sprintf(
+				"Host: %s\nConsecutive failed attempts: %d\nRuns are short-circuited for %s before the host is probed again",
+				host, c.failures, b.cfg.OpenTimeout))
+		}
+
+	case circuitHalfOpen:
+		if result == attemptFailed {
+			b.open(c)
+			b.logger.Info("Probe Failed, Circuit Reopened", zap.String("host", host))
+			return
+		}
+		delete(b.circuits, host)
+		down := time.Since(c.downSince).Round(time.Second)
+		b.logger.Info("Circuit Closed For Target Host",
+			zap.String("host", host), zap.Duration("down", down), zap.Int("shortCircuited", c.shortCircuited))
+		b.notify("Circuit Closed For Target Host", fmt.Sprintf(
+			"Host: %s\nRecovered after: %s\nShort-circuited runs: %d", host, down, c.shortCircuited))
+	}
+}
+
+// open moves the circuit to open. Callers hold mu.
+func (b *Breaker) open(c *circuit) {
+	c.state = circuitOpen
+	c.gen++
+	c.openedAt = time.Now()
+	c.probes = 0
+}
+
+// notify sends an alert without holding up the attempt that triggered it.
+func (b *Breaker) notify(title, message string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), statusUpdateTimeout)
+		defer cancel()
+		if err := b.alerts.SendNotice(ctx, title, message); err != nil {
+			b.logger.Error("Error Sending Slack Alert", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,91 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	notifications "scheduler/utils/notifications"
+
+	// External Packages
+	"go.uber.org/zap"
+)
+
+// noticeRecorder collects the titles of notices sent by the breaker.
+type noticeRecorder struct {
+	notifications.Sender
+	titles chan string
+}
+
+func (r *noticeRecorder) SendNotice(_ context.Context, title, _ string) error {
+	r.titles <- title
+	return nil
+}
+
+func TestBreaker(t *testing.T) {
+	const host = "api.example.com"
+	const opened, closed = "Circuit Opened For Target Host", "Circuit Closed For Target Host"
+	cfg := config.Breaker{Enabled: true, FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1}
+
+	// Each step is "ok", "fail" or "ignore" for an attempt that must be
+	// allowed, "deny" for one that must be short-circuited, or "expire" to
+	// let the open timeout pass.
+	tests := []struct {
+		name        string
+		steps       []string
+		wantState   circuitState
+		wantCircuit bool // false when the host holds no state
+		wantNotices []string
+	}{
+		{"success resets the failures", []string{"fail", "ok", "fail"}, circuitClosed, true, nil},
+		{"ignored attempts do not count", []string{"fail", "ignore", "fail", "deny"}, circuitOpen, true, []string{opened}},
+		{"probe success closes it", []string{"fail", "fail", "deny", "expire", "ok"}, 0, false, []string{opened, closed}},
+		{"probe failure reopens it", []string{"fail", "fail", "expire", "fail", "deny"}, circuitOpen, true, []string{opened}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := &noticeRecorder{titles: make(chan string, 10)}
+			b := NewBreaker(cfg, zap.NewNop(), alerts)
+			results := map[string]attemptResult{"ok": attemptSucceeded, "fail": attemptFailed, "ignore": attemptIgnored}
+
+			for i, step := range tt.steps {
+				if step == "expire" {
+					b.circuits[host].openedAt = time.Now().Add(-cfg.OpenTimeout)
+					continue
+				}
+				done, err := b.Allow(host)
+				if (err != nil) != (step == "deny") {
+					t.Fatalf("step %d (%s): Allow() error = %v", i, step, err)
+				}
+				if err == nil {
+					done(results[step])
+				}
+			}
+
+			c, ok := b.circuits[host]
+			if ok != tt.wantCircuit {
+				t.Fatalf("circuit kept = %v, want %v", ok, tt.wantCircuit)
+			}
+			if ok && c.state != tt.wantState {
+				t.Errorf("state = %d, want %d", c.state, tt.wantState)
+			}
+			var notices []string
+			for range tt.wantNotices {
+				select {
+				case title := <-alerts.titles:
+					notices = append(notices, title)
+				case <-time.After(time.Second):
+				}
+			}
+			// Notices are sent concurrently, so only their set is compared.
+			slices.Sort(notices)
+			if want := slices.Sorted(slices.Values(tt.wantNotices)); !slices.Equal(notices, want) {
+				t.Errorf("notices = %v, want %v", notices, tt.wantNotices)
+			}
+		})
+	}
+}
@@ -3,75 +3,312 @@ package executer
 import (
 	// Go Internal Packages
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io"
-	"math/rand"
+	mrand "math/rand"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	// Local Packages
+	errors "scheduler/errors"
 	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
 	httpclient "scheduler/utils/httpclient"
 	notifications "scheduler/utils/notifications"
+	secrets "scheduler/utils/secrets"
 
 	// External Packages
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// ErrSecretResolution is reported when a task's secret references cannot be
+// resolved. The run fails without making any HTTP attempt.
+var ErrSecretResolution = errors.New("secret resolution failed")
+
+// ErrRunCancelled is the cancellation cause of a run stopped through the API.
+// Such runs are recorded as cancelled and do not alert.
+var ErrRunCancelled = errors.New("execution cancelled")
+
+// ErrShuttingDown is the cancellation cause of runs still going when the
+// scheduler's drain period ends. Such runs are recorded as cancelled and do not alert.
+var ErrShuttingDown = errors.New("execution cancelled: scheduler shutting down")
+
+// statusUpdateTimeout bounds status writes, which use a context detached from
+// the run so that they still happen after the run is cancelled.
+const statusUpdateTimeout = 10 * time.Second
+
+// maxRecordedBody bounds how much of a failed response is kept in a dead letter.
+const maxRecordedBody = 4 << 10
+
+// maxPollBody bounds how much of a job handle or status response is read.
+const maxPollBody = 1 << 20
+
+// pollRequestTimeout bounds each status request of a polled job.
+const pollRequestTimeout = 30 * time.Second
+
 type SchedulerRepo interface {
-	UpdateTaskStatus(ctx context.Context, taskID, exceptionMsg string, isComplete bool) error
+	UpdateTaskStatus(ctx context.Context, taskID string, outcome models.Outcome, exceptionMsg string) error
+	// RetrySkipped sets the next run of a non-recurring task whose fire was skipped.
+	RetrySkipped(ctx context.Context, taskID string, nextRunAt int64) error
+	// ReleaseClaim removes the claim of the given fire once its run has ended.
+	ReleaseClaim(ctx context.Context, taskID string, fireAt int64) error
+}
+
+// DeadLetterStore keeps runs that exhausted their attempts.
+type DeadLetterStore interface {
+	Insert(ctx context.Context, dl models.DeadLetter) error
+}
+
+// CompletionStore keeps runs that wait for their result after the request.
+type CompletionStore interface {
+	Insert(ctx context.Context, pc models.PendingCompletion) error
+	// Delete removes a pending completion, reporting whether it was still pending.
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+type ExecutionQuota interface {
+	AllowExecution(ctx context.Context, namespace string) (allowed bool, refund func(), err error)
+}
+
+// RunTracker registers runs while they are in flight so they can be listed,
+// cancelled and drained. Begin returns the context the run must use and a
+// handle to report its progress on. A tracker that no longer accepts runs
+// returns an already cancelled context.
+type RunTracker interface {
+	Begin(ctx context.Context, task models.Task, trigger models.Trigger) (context.Context, RunProgress)
+}
+
+// RunProgress receives the progress of a run registered with a RunTracker.
+type RunProgress interface {
+	// Attempt records that attempt n is starting.
+	Attempt(n int)
+	// Backoff records that the next attempt is due at until.
+	Backoff(until time.Time)
+	// End removes the run from the tracker once it is finished.
+	End()
+}
+
+// Dependencies are the services shared by every executor.
+type Dependencies struct {
+	Logger      *zap.Logger
+	Repo        SchedulerRepo
+	Slack       notifications.Sender
+	Client      *httpclient.Client
+	Secrets     *secrets.Resolver
+	Quota       ExecutionQuota
+	Runs        RunTracker
+	Pool        *Pool
+	Limiter     *RateLimiter
+	Breaker     *Breaker
+	DeadLetters DeadLetterStore
+	Completions CompletionStore
+	// CallbackURL is the URL callback IDs are appended to; empty disables
+	// asynchronous completion.
+	CallbackURL string
 }
 
 type ExecutorService struct {
-	ctx    context.Context
-	logger *zap.Logger
-	task   models.Task
-	repo   SchedulerRepo
-	slack  notifications.Sender
-	client *httpclient.Client
+	ctx     context.Context
+	task    models.Task
+	trigger models.Trigger
+	*Dependencies
 }
 
-func NewExecutorService(ctx context.Context, logger *zap.Logger, task models.Task, repo SchedulerRepo, slack notifications.Sender, client *httpclient.Client) *ExecutorService {
+func NewExecutorService(ctx context.Context, task models.Task, trigger models.Trigger, deps *Dependencies) *ExecutorService {
 	return &ExecutorService{
-		ctx:    ctx,
-		logger: logger,
-		task:   task,
-		repo:   repo,
-		slack:  slack,
-		client: client,
+		ctx:          ctx,
+		task:         task,
+		trigger:      trigger,
+		Dependencies: deps,
+	}
+}
+
+// Start registers the run with the tracker and executes it in a new goroutine.
+// It reports false when the tracker refused the run, e.g. during shutdown.
+func (s *ExecutorService) Start() bool {
+	runCtx, progress, ok := s.begin()
+	if ok {
+		go s.run(runCtx, progress)
 	}
+	return ok
 }
 
-func (s *ExecutorService) Run() {
+// Run registers the run with the tracker and executes it, returning once the
+// run has ended. It reports false when the tracker refused the run.
+func (s *ExecutorService) Run() bool {
+	runCtx, progress, ok := s.begin()
+	if ok {
+		s.run(runCtx, progress)
+	}
+	return ok
+}
+
+// Abandon records a run that will not be attempted again, e.g. a queued job
+// that was delivered too often, as failed. It is stored as a dead letter and
+// alerts like any other failed run.
+func (s *ExecutorService) Abandon(exceptionMsg string) {
+	s.deadLetter(s.ctx, nil, exceptionMsg)
+	s.fail(s.ctx, exceptionMsg)
+}
+
+func (s *ExecutorService) begin() (context.Context, RunProgress, bool) {
+	runCtx, progress := s.Runs.Begin(s.ctx, s.task, s.trigger)
+	if runCtx.Err() != nil {
+		progress.End()
+		s.Logger.Info("Run Not Started", zap.String("taskId", s.task.ID), zap.Error(context.Cause(runCtx)))
+		return nil, nil, false
+	}
+	return runCtx, progress, true
+}
+
+func (s *ExecutorService) run(runCtx context.Context, progress RunProgress) {
+	defer progress.End()
+	defer s.releaseClaim(runCtx)
 	attempts := s.task.NumberOfAttempts
 	data := s.task.TaskData
 
-	ctx, cancel := context.WithTimeout(s.ctx, 2*time.Minute)
+	// Queue for an execution slot before the run's timeout starts.
+	release, err := s.Pool.Acquire(runCtx, data.TargetHost())
+	if err != nil {
+		if s.cancelled(runCtx) {
+			return
+		}
+		s.Logger.Warn("Execution Queue Wait Exceeded, Skipping Run",
+			zap.String("taskId", s.task.ID), zap.String("host", data.TargetHost()), zap.Error(err))
+		s.updateStatus(runCtx, models.OutcomeSkipped, err.Error())
+		return
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(runCtx, 2*time.Minute)
 	defer cancel()
 
+	allowed, refund, err := s.Quota.AllowExecution(ctx, s.task.Namespace)
+	if err != nil {
+		// Fail open: a usage store outage should not stop every task from running.
+		s.Logger.Error("Failed To Check Execution Quota", zap.String("taskId", s.task.ID), zap.Error(err))
+	} else if !allowed {
+		s.Logger.Warn("Namespace Execution Quota Exceeded, Skipping Run",
+			zap.String("taskId", s.task.ID), zap.String("namespace", s.task.Namespace))
+		s.updateStatus(ctx, models.OutcomeSkipped, "namespace execution quota exceeded")
+		return
+	}
+	// Only runs that send a request count toward the quota.
+	sent := false
+	defer func() {
+		if !sent {
+			refund()
+		}
+	}()
+
+	req, err := s.buildRequest(ctx)
+	if err != nil {
+		if s.cancelled(ctx) {
+			return
+		}
+		s.Logger.Error("Failed To Build Request", zap.String("taskId", s.task.ID), zap.Error(err))
+		s.fail(ctx, err.Error())
+		return
+	}
+
+	// An accepted run is completed by its callback or the sweeper instead;
+	// otherwise its pending completion is withdrawn when the run ends.
+	accepted := false
+	if s.task.Completion.Is(models.CompletionCallback) {
+		callbackID, err := s.awaitCallback(ctx, &req)
+		if err != nil {
+			s.Logger.Error("Failed To Register Callback", zap.String("taskId", s.task.ID), zap.Error(err))
+			s.fail(ctx, err.Error())
+			return
+		}
+		defer func() {
+			if !accepted {
+				s.withdrawCallback(ctx, callbackID)
+			}
+		}()
+	}
+
+	started := time.Now()
 	baseDelay := 500 * time.Millisecond
+	records := make([]models.AttemptRecord, 0, attempts)
 	for attempt := 1; attempt <= attempts; attempt++ {
-		resp, err := s.client.Do(ctx, httpclient.Request{
-			URL:         data.URL,
-			Method:      data.RequestType,
-			Headers:     data.Headers,
-			QueryParams: data.QueryParams,
-			Body:        data.RequestBody,
-		})
+		if !s.throttle(ctx, progress, records) {
+			return
+		}
+		done, err := s.Breaker.Allow(data.TargetHost())
+		if err != nil && attempt > 1 {
+			// Requests were already sent, so the run failed rather than never started.
+			s.Logger.Error("Circuit Opened Between Attempts, Task Failed", zap.String("taskId", s.task.ID), zap.Error(err))
+			s.deadLetter(ctx, records, err.Error())
+			s.fail(ctx, err.Error())
+			return
+		}
+		if err != nil {
+			s.Logger.Warn("Circuit Open For Target Host, Short-Circuiting Run", zap.String("taskId", s.task.ID), zap.Error(err))
+			s.updateStatus(ctx, models.OutcomeShortCircuited, err.Error())
+			return
+		}
+		progress.Attempt(attempt)
+		record := models.AttemptRecord{Attempt: attempt, StartedAt: helpers.GetCurrentDateTime()}
+		sent = true
+		resp, err := s.Client.Do(ctx, req)
+		done(s.attemptResult(ctx, resp, err))
+		var handle string
 		if resp != nil {
+			record.StatusCode = resp.StatusCode
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				record.ResponseBody = readPrefix(resp.Body, maxRecordedBody)
+			} else if s.task.Completion.Is(models.CompletionPoll) {
+				handle = readPrefix(resp.Body, maxPollBody)
+			}
 			_, _ = io.Copy(io.Discard, resp.Body)
 			_ = resp.Body.Close()
 		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		record.EndedAt = helpers.GetCurrentDateTime()
+		records = append(records, record)
 
+		if err == nil && resp != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 && s.task.Completion.Is(models.CompletionCallback) {
+			s.Logger.Info("Task Accepted, Awaiting Callback",
+				zap.String("taskId", s.task.ID), zap.Int("statusCode", resp.StatusCode))
+			accepted = true
+			return
+		}
+		if err == nil && resp != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 && s.task.Completion.Is(models.CompletionPoll) {
+			s.Logger.Info("Job Accepted, Polling Status",
+				zap.String("taskId", s.task.ID), zap.Int("statusCode", resp.StatusCode))
+			// Waiting on the job does not hold an execution slot.
+			release()
+			s.pollStatus(runCtx, req, started, resp.Header.Get("Location"), handle, progress)
+			return
+		}
 		if err == nil && resp != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			s.logger.Info("Task Executed Successfully",
+			s.Logger.Info("Task Executed Successfully",
 				zap.String("taskId", s.task.ID), zap.Int("statusCode", resp.StatusCode))
+			s.updateStatus(ctx, models.OutcomeSucceeded, "")
+			return
+		}
 
-			if updateErr := s.repo.UpdateTaskStatus(ctx, s.task.ID, "", true); updateErr != nil {
-				s.logger.Error("Failed To Update Task Status", zap.Error(updateErr))
-			}
+		if s.cancelled(ctx) {
+			return
+		}
+
+		// A blocked destination will not become allowed on retry.
+		if errors.Is(err, httpclient.ErrBlockedDestination) {
+			s.Logger.Error("Destination Blocked By Outbound Policy", zap.String("taskId", s.task.ID), zap.Error(err))
+			s.fail(ctx, err.Error())
 			return
 		}
 
-		s.logger.Warn("Task Execution Failed, Retrying",
+		s.Logger.Warn("Task Execution Failed, Retrying",
 			zap.String("taskId", s.task.ID),
 			zap.Int("attempt", attempt),
 			zap.Error(err),
@@ -80,33 +317,403 @@ func (s *ExecutorService) Run() {
 		if attempt == attempts {
 			exceptionMsg := ""
 			if resp != nil {
-				s.logger.Warn("API Call Failed", zap.String("url", data.URL), zap.String("status", resp.Status))
+				s.Logger.Warn("API Call Failed", zap.String("url", data.URL), zap.String("status", resp.Status))
 				exceptionMsg = resp.Status
 			}
 			if err != nil {
 				exceptionMsg = err.Error()
 			}
 
-			s.logger.Error("Max Retry Attempts Reached, Task Failed", zap.String("taskId", s.task.ID))
+			s.Logger.Error("Max Retry Attempts Reached, Task Failed", zap.String("taskId", s.task.ID))
+			s.deadLetter(ctx, records, exceptionMsg)
+			s.fail(ctx, exceptionMsg)
+			return
+		}
 
-			if updateErr := s.repo.UpdateTaskStatus(ctx, s.task.ID, exceptionMsg, false); updateErr != nil {
-				s.logger.Error("Failed To Update Task Status", zap.Error(updateErr))
-			}
+		jitter := time.Duration(mrand.Intn(300)) * time.Millisecond
+		backoff := time.Duration(attempt)*baseDelay + jitter
+		progress.Backoff(time.Now().Add(backoff))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			s.interrupted(ctx, records)
+			return
+		}
+	}
+}
+
+// throttle waits until the task's rate limits allow its next request. The
+// wait does not use up an attempt. It reports false when the run ended instead;
+// records are the attempts made so far.
+func (s *ExecutorService) throttle(ctx context.Context, progress RunProgress, records []models.AttemptRecord) bool {
+	delay, err := s.Limiter.Reserve(ctx, s.task)
+	if errors.Is(err, ErrRateLimitWait) {
+		s.Logger.Warn("Rate Limit Wait Exceeds Run Timeout, Skipping Run", zap.String("taskId", s.task.ID))
+		s.updateStatus(ctx, models.OutcomeSkipped, err.Error())
+		return false
+	}
+	if err != nil {
+		// Fail open: a rate limit store outage should not stop every task from running.
+		s.Logger.Error("Failed To Reserve Rate Limit", zap.String("taskId", s.task.ID), zap.Error(err))
+		return true
+	}
+	if delay <= 0 {
+		return true
+	}
+
+	s.Logger.Debug("Request Throttled By Rate Limit", zap.String("taskId", s.task.ID), zap.Duration("delay", delay))
+	progress.Backoff(time.Now().Add(delay))
+	timer := time.NewTimer(delay)
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		timer.Stop()
+		s.interrupted(ctx, records)
+		return false
+	}
+}
+
+// interrupted records a run whose context ended while it waited between
+// requests. A run that was not cancelled hit its timeout and failed, with the
+// attempts made so far stored as a dead letter.
+func (s *ExecutorService) interrupted(ctx context.Context, records []models.AttemptRecord) {
+	if s.cancelled(ctx) {
+		return
+	}
+	exceptionMsg := fmt.Sprintf("run timed out after %d attempts: %v", len(records), ctx.Err())
+	s.Logger.Error("Task Execution Timed Out", zap.String("taskId", s.task.ID), zap.Int("attempts", len(records)))
+	s.deadLetter(ctx, records, exceptionMsg)
+	s.fail(ctx, exceptionMsg)
+}
 
-			if sendErr := s.slack.SendAlert(ctx, s.task, exceptionMsg); sendErr != nil {
-				s.logger.Error("Error Sending Slack Alert", zap.Error(sendErr))
+// attemptResult classifies an attempt for the circuit breaker. Responses
+// below 500 show the host is up, even if the request was rejected.
+func (s *ExecutorService) attemptResult(ctx context.Context, resp *http.Response, err error) attemptResult {
+	cause := context.Cause(ctx)
+	switch {
+	case errors.Is(cause, ErrRunCancelled) || errors.Is(cause, ErrShuttingDown):
+		return attemptIgnored
+	case errors.Is(err, httpclient.ErrBlockedDestination):
+		return attemptIgnored
+	case err != nil || resp == nil || resp.StatusCode >= 500:
+		return attemptFailed
+	}
+	return attemptSucceeded
+}
+
+// cancelled reports whether the run was cancelled through the API or by
+// shutdown, recording it as cancelled if so.
+func (s *ExecutorService) cancelled(ctx context.Context) bool {
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, ErrRunCancelled) && !errors.Is(cause, ErrShuttingDown) {
+		return false
+	}
+	s.Logger.Info("Task Execution Cancelled", zap.String("taskId", s.task.ID), zap.Error(cause))
+	s.updateStatus(ctx, models.OutcomeCancelled, cause.Error())
+	return true
+}
+
+// buildRequest resolves secret references in the task's headers, query params
+// and body, and decodes base64 content. Values are resolved once per run so every attempt sends the same request.
+func (s *ExecutorService) buildRequest(ctx context.Context) (httpclient.Request, error) {
+	data := s.task.TaskData
+	headers, err := s.Secrets.ExpandStrings(ctx, data.Headers)
+	if err != nil {
+		return httpclient.Request{}, fmt.Errorf("%w: %w", ErrSecretResolution, err)
+	}
+	queryParams, err := s.Secrets.ExpandValues(ctx, data.QueryParams)
+	if err != nil {
+		return httpclient.Request{}, fmt.Errorf("%w: %w", ErrSecretResolution, err)
+	}
+
+	req := httpclient.Request{
+		URL:           data.URL,
+		Method:        data.RequestType,
+		Headers:       headers,
+		QueryParams:   queryParams,
+		QueryEncoding: data.QueryEncoding,
+		BodyType:      data.BodyType,
+	}
+	switch data.BodyType {
+	case httpclient.BodyRaw:
+		body, err := s.Secrets.Expand(ctx, data.RawBody)
+		if err != nil {
+			return httpclient.Request{}, fmt.Errorf("%w: %w", ErrSecretResolution, err)
+		}
+		req.Body = body
+	case httpclient.BodyBinary:
+		body, err := base64.StdEncoding.DecodeString(data.RawBody)
+		if err != nil {
+			return httpclient.Request{}, fmt.Errorf("invalid binary body: %w", err)
+		}
+		req.Body = body
+	default:
+		body, err := s.Secrets.ExpandValues(ctx, data.RequestBody)
+		if err != nil {
+			return httpclient.Request{}, fmt.Errorf("%w: %w", ErrSecretResolution, err)
+		}
+		// Keep a nil body nil so no JSON "null" body or Content-Type is sent.
+		if body != nil {
+			req.Body = body
+		}
+		for _, f := range data.Files {
+			content, err := base64.StdEncoding.DecodeString(f.Content)
+			if err != nil {
+				return httpclient.Request{}, fmt.Errorf("invalid content of file %s: %w", f.Filename, err)
 			}
-			return
+			req.Files = append(req.Files, httpclient.File{
+				Field: f.Field, Filename: f.Filename, ContentType: f.ContentType, Content: content,
+			})
 		}
+	}
+	return req, nil
+}
 
-		jitter := time.Duration(rand.Intn(300)) * time.Millisecond
-		backoff := time.Duration(attempt) * baseDelay
-		timer := time.NewTimer(backoff + jitter)
+// pollStatus polls the status URL of an accepted job until it matches the
+// success or failure condition, or until the completion timeout, counted from
+// started, passes. Failed status requests are retried at the next interval.
+func (s *ExecutorService) pollStatus(runCtx context.Context, req httpclient.Request, started time.Time, location, handle string, progress RunProgress) {
+	poll := s.task.Completion.Poll
+	timeout := time.Duration(s.task.Completion.TimeoutSeconds) * time.Second
+	ctx, cancel := context.WithDeadline(runCtx, started.Add(timeout))
+	defer cancel()
+
+	// A handle that is not JSON still works with a Location header.
+	var doc any
+	_ = json.Unmarshal([]byte(handle), &doc)
+	statusURL, err := poll.StatusURLFor(s.task.TaskData.URL, doc, location)
+	if err != nil {
+		s.Logger.Error("Failed To Derive Status URL", zap.String("taskId", s.task.ID), zap.Error(err))
+		s.fail(ctx, err.Error())
+		return
+	}
+	// The task's headers may hold resolved secrets, so they are only sent to
+	// the task's own origin, not to any host the response names.
+	statusReq := httpclient.Request{URL: statusURL, Method: http.MethodGet}
+	if sameOrigin(s.task.TaskData.URL, statusURL) {
+		statusReq.Headers = req.Headers
+	}
+
+	interval := time.Duration(poll.IntervalSeconds) * time.Second
+	lastErr := ""
+	for {
+		progress.Backoff(time.Now().Add(interval))
+		timer := time.NewTimer(interval)
 		select {
 		case <-timer.C:
 		case <-ctx.Done():
 			timer.Stop()
+			if s.cancelled(ctx) {
+				return
+			}
+			exceptionMsg := fmt.Sprintf("job did not complete within %s", timeout)
+			if lastErr != "" {
+				exceptionMsg += "; last poll: " + lastErr
+			}
+			s.Logger.Error("Job Polling Timed Out, Task Failed", zap.String("taskId", s.task.ID))
+			s.fail(ctx, exceptionMsg)
+			return
+		}
+
+		status, err := s.pollOnce(ctx, statusReq)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.Logger.Warn("Status Poll Failed, Retrying", zap.String("taskId", s.task.ID), zap.Error(err))
+				lastErr = err.Error()
+			}
+			continue
+		}
+		if _, ok := poll.Success.Matches(status); ok {
+			s.Logger.Info("Job Completed Successfully", zap.String("taskId", s.task.ID))
+			s.updateStatus(ctx, models.OutcomeSucceeded, "")
+			return
+		}
+		if value, ok := poll.Failure.Matches(status); ok {
+			s.Logger.Warn("Job Reported Failure", zap.String("taskId", s.task.ID), zap.String("state", value))
+			s.fail(ctx, fmt.Sprintf("job failed: %s is %q", poll.Failure.Path, value))
 			return
 		}
+		lastErr = ""
+	}
+}
+
+// sameOrigin reports whether both URLs have the same scheme, host and port.
+func sameOrigin(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(ua.Hostname(), ub.Hostname()) &&
+		originPort(ua) == originPort(ub)
+}
+
+func originPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	}
+	return ""
+}
+
+// pollOnce fetches the job's status and decodes it as JSON.
+func (s *ExecutorService) pollOnce(ctx context.Context, req httpclient.Request) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+	defer cancel()
+	resp, err := s.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("status url returned %s", resp.Status)
+	}
+	var status any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPollBody)).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid status response: %w", err)
+	}
+	return status, nil
+}
+
+// awaitCallback registers the run as waiting for its result and adds the
+// callback URL and one-time token to the request. Only a hash of the token is
+// stored. It returns the callback ID.
+func (s *ExecutorService) awaitCallback(ctx context.Context, req *httpclient.Request) (string, error) {
+	if s.CallbackURL == "" {
+		return "", errors.New("callback base url not configured")
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate callback token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	timeout := s.task.Completion.TimeoutSeconds
+	if timeout == 0 {
+		timeout = models.DefaultCompletionTimeout
+	}
+	pc := models.PendingCompletion{
+		ID:             uuid.New().String(),
+		TaskID:         s.task.ID,
+		Namespace:      s.task.Namespace,
+		Trigger:        s.trigger,
+		Mode:           s.task.Completion.Mode,
+		TokenHash:      helpers.SHA256(token),
+		StartedAt:      helpers.GetCurrentDateTime(),
+		TimeoutSeconds: timeout,
+		Deadline:       int64(helpers.CurrentUTCUnix()) + int64(timeout),
+	}
+	if err := s.Completions.Insert(ctx, pc); err != nil {
+		return "", fmt.Errorf("failed to register callback: %w", err)
+	}
+
+	headers := make(map[string]string, len(req.Headers)+2)
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+	headers[models.CallbackURLHeader] = s.CallbackURL + pc.ID
+	headers[models.CallbackTokenHeader] = token
+	req.Headers = headers
+	return pc.ID, nil
+}
+
+// withdrawCallback removes the pending completion of a run that was not
+// accepted, so that a late callback is rejected.
+func (s *ExecutorService) withdrawCallback(ctx context.Context, id string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusUpdateTimeout)
+	defer cancel()
+	if _, err := s.Completions.Delete(ctx, id); err != nil {
+		s.Logger.Error("Failed To Withdraw Callback", zap.String("taskId", s.task.ID), zap.Error(err))
+	}
+}
+
+// deadLetter stores the run, with the request as defined and every attempt
+// made, so that it can be inspected and replayed.
+func (s *ExecutorService) deadLetter(ctx context.Context, records []models.AttemptRecord, exceptionMsg string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusUpdateTimeout)
+	defer cancel()
+	failedAt := helpers.GetCurrentDateTime()
+	startedAt := failedAt
+	if len(records) > 0 {
+		startedAt = records[0].StartedAt
+	}
+	dl := models.DeadLetter{
+		ID:        uuid.New().String(),
+		TaskID:    s.task.ID,
+		Namespace: s.task.Namespace,
+		TaskType:  s.task.TaskData.TaskType,
+		Trigger:   s.trigger,
+		Request:   s.task.TaskData,
+		Attempts:  records,
+		StartedAt: startedAt,
+		FailedAt:  failedAt,
+		Error:     exceptionMsg,
+	}
+	if err := s.DeadLetters.Insert(ctx, dl); err != nil {
+		s.Logger.Error("Failed To Store Dead Letter", zap.String("taskId", s.task.ID), zap.Error(err))
+	}
+}
+
+// readPrefix reads up to n bytes of r as text.
+func readPrefix(r io.Reader, n int64) string {
+	b, _ := io.ReadAll(io.LimitReader(r, n))
+	return string(b)
+}
+
+// fail records the run as failed and sends a Slack alert.
+func (s *ExecutorService) fail(ctx context.Context, exceptionMsg string) {
+	s.updateStatus(ctx, models.OutcomeFailed, exceptionMsg)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusUpdateTimeout)
+	defer cancel()
+	if sendErr := s.Slack.SendAlert(ctx, s.task, exceptionMsg); sendErr != nil {
+		s.Logger.Error("Error Sending Slack Alert", zap.Error(sendErr))
+	}
+}
+
+// releaseClaim removes the claim of the fire the run was started for, if any,
+// now that its outcome is recorded.
+func (s *ExecutorService) releaseClaim(ctx context.Context) {
+	if s.task.Claim == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusUpdateTimeout)
+	defer cancel()
+	if err := s.Repo.ReleaseClaim(ctx, s.task.ID, s.task.Claim.FireAt); err != nil {
+		s.Logger.Error("Failed To Release Claim", zap.String("taskId", s.task.ID), zap.Error(err))
+	}
+}
+
+// updateStatus records the outcome of the run, even if ctx is already done.
+func (s *ExecutorService) updateStatus(ctx context.Context, outcome models.Outcome, exceptionMsg string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusUpdateTimeout)
+	defer cancel()
+	if updateErr := s.Repo.UpdateTaskStatus(ctx, s.task.ID, outcome, exceptionMsg); updateErr != nil {
+		s.Logger.Error("Failed To Update Task Status", zap.Error(updateErr))
+	}
+	if outcome.Ran() {
+		return
+	}
+	// The claim left a non-recurring task without a next run, so a skipped
+	// fire would otherwise never happen.
+	if next, ok := s.task.RetryAfterSkip(helpers.CurrentUTCUnix()); ok {
+		if retryErr := s.Repo.RetrySkipped(ctx, s.task.ID, int64(next)); retryErr != nil {
+			s.Logger.Error("Failed To Retry Skipped Task", zap.Error(retryErr))
+		}
 	}
 }
@@ -0,0 +1,29 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"testing"
+)
+
+func TestSameOrigin(t *testing.T) {
+	const taskURL = "https://api.example.com/v1/exports"
+	tests := []struct {
+		statusURL string
+		want      bool
+	}{
+		{"https://api.example.com/jobs/1", true},
+		{"https://API.example.com:443/jobs/1", true},
+		{"http://api.example.com/jobs/1", false},
+		{"https://api.example.com:8443/jobs/1", false},
+		{"https://status.example.com/jobs/1", false},
+		{"https://api.example.com.evil.test/jobs/1", false},
+		{"://bad", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.statusURL, func(t *testing.T) {
+			if got := sameOrigin(taskURL, tt.statusURL); got != tt.want {
+				t.Errorf("sameOrigin(%q) = %v, want %v", tt.statusURL, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+	models "scheduler/models"
+)
+
+// ErrQueueWaitExceeded is reported when a fire waited longer than the
+// configured maximum for an execution slot. The run is skipped.
+var ErrQueueWaitExceeded = errors.New("execution queue wait exceeded")
+
+// Pool bounds how many executions run at once, in total and per target host.
+// Fires beyond the limits wait in FIFO order; a fire whose host is at its
+// limit does not hold up fires for other hosts.
+type Pool struct {
+	maxConcurrent int
+	maxPerHost    int
+	maxWait       time.Duration
+
+	mu      sync.Mutex
+	running int
+	hosts   map[string]*hostUsage
+	waiters *list.List // of *poolWaiter
+}
+
+type hostUsage struct {
+	running int
+	queued  int
+}
+
+type poolWaiter struct {
+	host     string
+	queuedAt time.Time
+	ready    chan struct{}
+	granted  bool
+}
+
+func NewPool(cfg config.Execution) *Pool {
+	return &Pool{
+		maxConcurrent: cfg.MaxConcurrent,
+		maxPerHost:    cfg.MaxPerHost,
+		maxWait:       cfg.MaxQueueWait,
+		hosts:         make(map[string]*hostUsage),
+		waiters:       list.New(),
+	}
+}
+
+// Acquire waits for an execution slot for host and returns the function that
+// frees it. It fails with ErrQueueWaitExceeded after the maximum queue wait,
+// or with the cause of ctx when ctx is done first.
+func (p *Pool) Acquire(ctx context.Context, host string) (release func(), err error) {
+	p.mu.Lock()
+	w := &poolWaiter{host: host, queuedAt: time.Now(), ready: make(chan struct{})}
+	elem := p.waiters.PushBack(w)
+	p.usage(host).queued++
+	p.grant()
+	p.mu.Unlock()
+
+	timer := time.NewTimer(p.maxWait)
+	defer timer.Stop()
+	select {
+	case <-w.ready:
+		return p.releaser(host), nil
+	case <-timer.C:
+		err = ErrQueueWaitExceeded
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if w.granted {
+		// The slot was handed over while giving up; use it rather than leak it.
+		return p.releaser(host), nil
+	}
+	p.waiters.Remove(elem)
+	p.usage(host).queued--
+	p.forget(host)
+	return nil, err
+}
+
+// Stats reports the current usage of the pool.
+func (p *Pool) Stats() models.PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := models.PoolStats{
+		MaxConcurrent: p.maxConcurrent,
+		MaxPerHost:    p.maxPerHost,
+		Running:       p.running,
+		Queued:        p.waiters.Len(),
+		Hosts:         make(map[string]models.HostStats, len(p.hosts)),
+	}
+	if front := p.waiters.Front(); front != nil {
+		stats.OldestWaitSeconds = int64(time.Since(front.Value.(*poolWaiter).queuedAt) / time.Second)
+	}
+	for host, u := range p.hosts {
+		stats.Hosts[host] = models.HostStats{Running: u.running, Queued: u.queued}
+	}
+	return stats
+}
+
+func (p *Pool) releaser(host string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.running--
+			p.usage(host).running--
+			p.forget(host)
+			p.grant()
+		})
+	}
+}
+
+// grant hands free slots to waiters in queue order, skipping those whose host
+// is at its limit. Callers hold mu.
+func (p *Pool) grant() {
+	for elem := p.waiters.Front(); elem != nil; {
+		if p.maxConcurrent > 0 && p.running >= p.maxConcurrent {
+			return
+		}
+		next := elem.Next()
+		w := elem.Value.(*poolWaiter)
+		if p.fits(w.host) {
+			p.waiters.Remove(elem)
+			p.usage(w.host).queued--
+			p.take(w.host)
+			w.granted = true
+			close(w.ready)
+		}
+		elem = next
+	}
+}
+
+// fits reports whether a run for host may start now. Callers hold mu.
+func (p *Pool) fits(host string) bool {
+	if p.maxConcurrent > 0 && p.running >= p.maxConcurrent {
+		return false
+	}
+	return p.maxPerHost <= 0 || p.usage(host).running < p.maxPerHost
+}
+
+func (p *Pool) take(host string) {
+	p.running++
+	p.usage(host).running++
+}
+
+func (p *Pool) usage(host string) *hostUsage {
+	u, ok := p.hosts[host]
+	if !ok {
+		u = &hostUsage{}
+		p.hosts[host] = u
+	}
+	return u
+}
+
+// forget drops hosts with nothing running or queued so the map stays small.
+func (p *Pool) forget(host string) {
+	if u := p.hosts[host]; u != nil && u.running == 0 && u.queued == 0 {
+		delete(p.hosts, host)
+	}
+}
@@ -0,0 +1,53 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"slices"
+	"testing"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+)
+
+func TestPoolGrantOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxConcurrent int
+		maxPerHost    int
+		running       []string // hosts with a run in progress
+		queued        []string // hosts of the waiting fires, oldest first
+		want          []int    // positions in queued that get a slot
+	}{
+		{"oldest first within the global limit", 2, 0, nil, []string{"a", "b", "c"}, []int{0, 1}},
+		{"full pool grants nothing", 1, 0, []string{"a"}, []string{"b"}, nil},
+		{"host at its limit does not block others", 0, 1, []string{"a"}, []string{"a", "b", "a", "c"}, []int{1, 3}},
+		{"per-host limit applies within one pass", 2, 1, nil, []string{"a", "a", "b", "c"}, []int{0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPool(config.Execution{MaxConcurrent: tt.maxConcurrent, MaxPerHost: tt.maxPerHost, MaxQueueWait: time.Minute})
+			for _, host := range tt.running {
+				p.take(host)
+			}
+			waiters := make([]*poolWaiter, len(tt.queued))
+			for i, host := range tt.queued {
+				waiters[i] = &poolWaiter{host: host, ready: make(chan struct{})}
+				p.waiters.PushBack(waiters[i])
+				p.usage(host).queued++
+			}
+
+			p.grant()
+
+			var got []int
+			for i, w := range waiters {
+				if w.granted {
+					got = append(got, i)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("granted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+	models "scheduler/models"
+	httpclient "scheduler/utils/httpclient"
+)
+
+// ErrRateLimitWait is reported when a rate limit would hold a request past
+// the run's timeout. The run is skipped.
+var ErrRateLimitWait = errors.New("rate limit wait exceeds run timeout")
+
+// RateStore keeps the state of rate limits. Reserve books the next request
+// slot of key and returns how long the caller must wait for it. A slot further
+// than maxWait away is not booked and ok is false.
+//
+// Refund gives back a slot booked by Reserve whose request will not be sent.
+//
+// Limits are enforced as a token bucket using the generic cell rate
+// algorithm: one request is allowed every interval, and up to burst-1 unused
+// intervals may be spent at once.
+type RateStore interface {
+	Reserve(ctx context.Context, key string, interval time.Duration, burst int, maxWait time.Duration) (wait time.Duration, ok bool, err error)
+	Refund(ctx context.Context, key string, interval time.Duration) error
+}
+
+// RateLimiter throttles requests per target host, per the configured host
+// rules, and per task, per the task's own rateLimit.
+type RateLimiter struct {
+	hosts []config.HostRateLimit
+	store RateStore
+}
+
+func NewRateLimiter(cfg config.RateLimits, store RateStore) *RateLimiter {
+	hosts := make([]config.HostRateLimit, 0, len(cfg.Hosts))
+	for _, rule := range cfg.Hosts {
+		rule.Host = strings.ToLower(strings.TrimSpace(rule.Host))
+		hosts = append(hosts, rule)
+	}
+	return &RateLimiter{hosts: hosts, store: store}
+}
+
+// Reserve books a request for the task under every limit that applies to it
+// and returns how long to wait before sending it. It fails with
+// ErrRateLimitWait when the wait would outlast the deadline of ctx; the slots
+// already booked for the request are then given back.
+func (l *RateLimiter) Reserve(ctx context.Context, task models.Task) (time.Duration, error) {
+	maxWait := time.Duration(math.MaxInt64)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = time.Until(deadline)
+	}
+
+	var delay time.Duration
+	reserve := func(key string, interval time.Duration, burst int) error {
+		wait, ok, err := l.store.Reserve(ctx, key, interval, max(burst, 1), maxWait)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrRateLimitWait
+		}
+		delay = max(delay, wait)
+		return nil
+	}
+
+	// The first matching rule applies; its limit is shared by every host it matches.
+	var hostKey string
+	var hostInterval time.Duration
+	host := strings.ToLower(task.TaskData.TargetHost())
+	for _, rule := range l.hosts {
+		if httpclient.MatchHost(rule.Host, host) {
+			hostKey, hostInterval = "host:"+rule.Host, rule.Per/time.Duration(rule.Requests)
+			if err := reserve(hostKey, hostInterval, rule.Burst); err != nil {
+				return 0, err
+			}
+			break
+		}
+	}
+	if rl := task.RateLimit; rl != nil {
+		if err := reserve("task:"+task.ID, rl.Interval(), rl.Burst); err != nil {
+			if errors.Is(err, ErrRateLimitWait) && hostKey != "" {
+				// The request is not sent, so it must not use up the host's
+				// budget. On a store error it is sent anyway and keeps the slot.
+				// A failed refund only costs the host one slot.
+				_ = l.store.Refund(context.WithoutCancel(ctx), hostKey, hostInterval)
+			}
+			return 0, err
+		}
+	}
+	return delay, nil
+}
+
+// rateSweepInterval is how often MemoryRateStore drops refilled buckets.
+const rateSweepInterval = time.Minute
+
+// MemoryRateStore keeps rate limits in the process. A key whose bucket has
+// refilled holds no state, so such keys, e.g. of deleted tasks, are dropped.
+type MemoryRateStore struct {
+	mu      sync.Mutex
+	tat     map[string]time.Time // theoretical arrival time of the next request per key
+	sweepAt time.Time
+	now     func() time.Time
+}
+
+func NewMemoryRateStore() *MemoryRateStore {
+	return &MemoryRateStore{tat: make(map[string]time.Time), now: time.Now}
+}
+
+func (m *MemoryRateStore) Reserve(_ context.Context, key string, interval time.Duration, burst int, maxWait time.Duration) (time.Duration, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+	tat, ok := m.tat[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+	wait := max(tat.Sub(now)-time.Duration(burst-1)*interval, 0)
+	if wait > maxWait {
+		return wait, false, nil
+	}
+	m.tat[key] = tat.Add(interval)
+	return wait, true, nil
+}
+
+func (m *MemoryRateStore) Refund(_ context.Context, key string, interval time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tat, ok := m.tat[key]
+	if !ok {
+		return nil
+	}
+	if tat = tat.Add(-interval); tat.After(m.now()) {
+		m.tat[key] = tat
+	} else {
+		delete(m.tat, key)
+	}
+	return nil
+}
+
+// sweep drops the keys whose bucket has refilled. Called with mu held.
+func (m *MemoryRateStore) sweep(now time.Time) {
+	if now.Before(m.sweepAt) {
+		return
+	}
+	for key, tat := range m.tat {
+		if !tat.After(now) {
+			delete(m.tat, key)
+		}
+	}
+	m.sweepAt = now.Add(rateSweepInterval)
+}
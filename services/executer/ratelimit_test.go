@@ -0,0 +1,155 @@
+package executer
+
+import (
+	// Go Internal Packages
+	"context"
+	"testing"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+	models "scheduler/models"
+)
+
+// fakeClock is a settable clock for MemoryRateStore.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestRateStore() (*MemoryRateStore, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1_000_000, 0)}
+	store := NewMemoryRateStore()
+	store.now = clock.now
+	return store, clock
+}
+
+func TestMemoryRateStoreReserve(t *testing.T) {
+	type step struct {
+		advance  time.Duration // moved forward before the reservation
+		maxWait  time.Duration
+		wantWait time.Duration
+		wantOK   bool
+	}
+	tests := []struct {
+		name  string
+		burst int
+		steps []step
+	}{
+		{
+			name:  "one per interval",
+			burst: 1,
+			steps: []step{
+				{0, time.Hour, 0, true},
+				{0, time.Hour, time.Second, true},
+				{0, time.Hour, 2 * time.Second, true},
+			},
+		},
+		{
+			name:  "burst spends unused intervals at once",
+			burst: 3,
+			steps: []step{
+				{0, time.Hour, 0, true},
+				{0, time.Hour, 0, true},
+				{0, time.Hour, 0, true},
+				{0, time.Hour, time.Second, true},
+			},
+		},
+		{
+			name:  "bucket refills over time",
+			burst: 2,
+			steps: []step{
+				{0, time.Hour, 0, true},
+				{0, time.Hour, 0, true},
+				{0, time.Hour, time.Second, true},
+				{3 * time.Second, time.Hour, 0, true},
+				{0, time.Hour, 0, true},
+			},
+		},
+		{
+			name:  "wait beyond maxWait is not booked",
+			burst: 1,
+			steps: []step{
+				{0, time.Hour, 0, true},
+				{0, 500 * time.Millisecond, time.Second, false},
+				{0, time.Second, time.Second, true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, clock := newTestRateStore()
+			for i, s := range tt.steps {
+				clock.t = clock.t.Add(s.advance)
+				wait, ok, err := store.Reserve(context.Background(), "k", time.Second, tt.burst, s.maxWait)
+				if err != nil || wait != s.wantWait || ok != s.wantOK {
+					t.Fatalf("step %d: Reserve() = %v, %v, %v; want %v, %v", i, wait, ok, err, s.wantWait, s.wantOK)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryRateStoreRefund(t *testing.T) {
+	store, _ := newTestRateStore()
+	ctx := context.Background()
+	for range 3 {
+		_, _, _ = store.Reserve(ctx, "k", time.Second, 1, time.Hour)
+	}
+	if err := store.Refund(ctx, "k", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	// Two slots are left booked, so the next request waits two intervals.
+	if wait, _, _ := store.Reserve(ctx, "k", time.Second, 1, time.Hour); wait != 2*time.Second {
+		t.Errorf("wait after refund = %v; want 2s", wait)
+	}
+
+	// Refunding the only slot leaves nothing to keep.
+	_, _, _ = store.Reserve(ctx, "single", time.Second, 1, time.Hour)
+	_ = store.Refund(ctx, "single", time.Second)
+	if _, ok := store.tat["single"]; ok {
+		t.Error("refunded bucket is still stored")
+	}
+}
+
+func TestMemoryRateStoreEvictsRefilledBuckets(t *testing.T) {
+	store, clock := newTestRateStore()
+	ctx := context.Background()
+	_, _, _ = store.Reserve(ctx, "task:deleted", time.Second, 1, time.Hour)
+	_, _, _ = store.Reserve(ctx, "task:slow", time.Hour, 1, time.Hour)
+
+	clock.t = clock.t.Add(rateSweepInterval)
+	_, _, _ = store.Reserve(ctx, "task:other", time.Second, 1, time.Hour)
+
+	if _, ok := store.tat["task:deleted"]; ok {
+		t.Error("refilled bucket was not evicted")
+	}
+	if _, ok := store.tat["task:slow"]; !ok {
+		t.Error("bucket that is still draining was evicted")
+	}
+}
+
+func TestRateLimiterRefundsHostWhenTaskLimitRefuses(t *testing.T) {
+	store, _ := newTestRateStore()
+	limiter := NewRateLimiter(config.RateLimits{Hosts: []config.HostRateLimit{
+		{Host: "api.example.com", Requests: 10, Per: time.Second, Burst: 1},
+	}}, store)
+	task := models.Task{
+		ID:        "t1",
+		RateLimit: &models.RateLimit{Requests: 1, PerSeconds: 60},
+		TaskData:  models.Data{URL: "https://api.example.com/run"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := limiter.Reserve(ctx, task); err != nil {
+		t.Fatalf("first Reserve() error = %v", err)
+	}
+	hostTAT := store.tat["host:api.example.com"]
+	if _, err := limiter.Reserve(ctx, task); !errors.Is(err, ErrRateLimitWait) {
+		t.Fatalf("second Reserve() error = %v; want ErrRateLimitWait", err)
+	}
+	if got := store.tat["host:api.example.com"]; !got.Equal(hostTAT) {
+		t.Errorf("host slot was kept: tat %v, want %v", got, hostTAT)
+	}
+}
@@ -0,0 +1,136 @@
+package quota
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+
+	// External Packages
+	"github.com/google/uuid"
+)
+
+type TaskCounter interface {
+	Count(ctx context.Context, namespace string) (int64, error)
+}
+
+type UsageRepo interface {
+	IncrementExecutions(ctx context.Context, namespace string, now time.Time) (int64, error)
+	DecrementExecutions(ctx context.Context, namespace string, now time.Time) error
+	LockTasks(ctx context.Context, namespace, token string, now, until time.Time) (bool, error)
+	UnlockTasks(ctx context.Context, namespace, token string) error
+}
+
+const (
+	taskLockLease = 30 * time.Second      // a crashed holder's lock is taken over after this
+	taskLockWait  = 5 * time.Second       // how long a create waits for a busy namespace
+	taskLockRetry = 50 * time.Millisecond // poll interval while waiting
+)
+
+// QuotaService enforces per-namespace limits from config.
+type QuotaService struct {
+	tasks  TaskCounter
+	usage  UsageRepo
+	config config.Namespaces
+}
+
+func NewService(tasks TaskCounter, usage UsageRepo, cfg config.Namespaces) *QuotaService {
+	return &QuotaService{tasks: tasks, usage: usage, config: cfg}
+}
+
+// For returns the quota that applies to namespace.
+func (s *QuotaService) For(namespace string) config.Quota {
+	if q, ok := s.config.Quotas[namespace]; ok {
+		return q
+	}
+	return s.config.DefaultQuota
+}
+
+// ReserveTasks makes room for n new tasks in the namespace. While the
+// namespace has a max_tasks limit, the count and the caller's insert run under
+// the namespace's task lock so concurrent creates cannot both pass the check;
+// the caller must call release once its insert has finished. A Forbidden error
+// is returned if the tasks would exceed the limit.
+func (s *QuotaService) ReserveTasks(ctx context.Context, namespace string, n int) (release func(), err error) {
+	limit := s.For(namespace).MaxTasks
+	if limit <= 0 {
+		return func() {}, nil
+	}
+	release, err = s.lockTasks(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.tasks.Count(ctx, namespace)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	if count+int64(n) > int64(limit) {
+		release()
+		return nil, errors.NewError(errors.Forbidden, fmt.Sprintf("namespace task quota exceeded (max %d)", limit))
+	}
+	return release, nil
+}
+
+// lockTasks waits up to taskLockWait for the namespace's task lock.
+func (s *QuotaService) lockTasks(ctx context.Context, namespace string) (func(), error) {
+	token := uuid.New().String()
+	deadline := time.Now().Add(taskLockWait)
+	for {
+		now := time.Now()
+		ok, err := s.usage.LockTasks(ctx, namespace, token, now, now.Add(taskLockLease))
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock namespace tasks: %w", err)
+		}
+		if ok {
+			break
+		}
+		if now.After(deadline) {
+			return nil, errors.NewError(errors.Conflict, "namespace is busy creating tasks, retry later")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(taskLockRetry):
+		}
+	}
+	return func() {
+		// Release even if the request was cancelled; otherwise the namespace
+		// stays locked until the lease expires.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = s.usage.UnlockTasks(ctx, namespace, token)
+	}, nil
+}
+
+// AllowExecution counts one execution against the namespace's hourly quota
+// and reports whether it is within max_executions_per_hour. A refused
+// execution is not counted. The caller must call refund if the allowed run
+// ends without sending a request, so that only runs that did count.
+func (s *QuotaService) AllowExecution(ctx context.Context, namespace string) (allowed bool, refund func(), err error) {
+	limit := s.For(namespace).MaxExecutionsPerHour
+	if limit <= 0 {
+		return true, func() {}, nil
+	}
+	now := time.Now()
+	count, err := s.usage.IncrementExecutions(ctx, namespace, now)
+	if err != nil {
+		return false, func() {}, fmt.Errorf("failed to count executions: %w", err)
+	}
+	refund = func() {
+		// Refund even if the run was cancelled, and against the hour it was
+		// counted in.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = s.usage.DecrementExecutions(ctx, namespace, now)
+	}
+	if count > int64(limit) {
+		refund()
+		return false, func() {}, nil
+	}
+	return true, refund, nil
+}
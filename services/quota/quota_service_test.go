@@ -0,0 +1,145 @@
+package quota
+
+import (
+	// Go Internal Packages
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+)
+
+// fakeTasks counts tasks inserted by the test.
+type fakeTasks struct{ n atomic.Int64 }
+
+func (f *fakeTasks) Count(context.Context, string) (int64, error) { return f.n.Load(), nil }
+
+// fakeUsage keeps the namespace lock and execution counter in memory with the
+// repository's semantics.
+type fakeUsage struct {
+	UsageRepo
+	mu         sync.Mutex
+	token      string
+	expire     time.Time
+	executions int64
+}
+
+func (f *fakeUsage) IncrementExecutions(context.Context, string, time.Time) (int64, error) {
+	f.executions++
+	return f.executions, nil
+}
+
+func (f *fakeUsage) DecrementExecutions(context.Context, string, time.Time) error {
+	f.executions--
+	return nil
+}
+
+func (f *fakeUsage) LockTasks(_ context.Context, _, token string, now, until time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.token != "" && f.expire.After(now) {
+		return false, nil
+	}
+	f.token, f.expire = token, until
+	return true, nil
+}
+
+func (f *fakeUsage) UnlockTasks(_ context.Context, _, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.token == token {
+		f.token = ""
+	}
+	return nil
+}
+
+func TestReserveTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		existing int64
+		n        int
+		wantErr  bool
+	}{
+		{"unlimited", 0, 100, 5, false},
+		{"within limit", 10, 5, 5, false},
+		{"over limit", 10, 8, 3, true},
+		{"full", 10, 10, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, usage := &fakeTasks{}, &fakeUsage{}
+			tasks.n.Store(tt.existing)
+			s := NewService(tasks, usage, config.Namespaces{DefaultQuota: config.Quota{MaxTasks: tt.limit}})
+
+			release, err := s.ReserveTasks(context.Background(), "ns", tt.n)
+			if tt.wantErr {
+				var e *errors.Error
+				if !errors.As(err, &e) || e.Kind != errors.Forbidden {
+					t.Fatalf("ReserveTasks() error = %v; want Forbidden", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ReserveTasks() error = %v", err)
+				}
+				release()
+			}
+			if usage.token != "" {
+				t.Errorf("namespace still locked after ReserveTasks")
+			}
+		})
+	}
+}
+
+func TestReserveTasksConcurrent(t *testing.T) {
+	const limit = 5
+	tasks := &fakeTasks{}
+	s := NewService(tasks, &fakeUsage{}, config.Namespaces{DefaultQuota: config.Quota{MaxTasks: limit}})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := s.ReserveTasks(context.Background(), "ns", 1)
+			if err != nil {
+				return
+			}
+			defer release()
+			time.Sleep(time.Millisecond) // the insert
+			tasks.n.Add(1)
+		}()
+	}
+	wg.Wait()
+	if got := tasks.n.Load(); got != limit {
+		t.Errorf("created %d tasks; want %d", got, limit)
+	}
+}
+
+func TestAllowExecution(t *testing.T) {
+	usage := &fakeUsage{}
+	s := NewService(&fakeTasks{}, usage, config.Namespaces{DefaultQuota: config.Quota{MaxExecutionsPerHour: 2}})
+	ctx := context.Background()
+
+	allowed, refund, _ := s.AllowExecution(ctx, "ns")
+	if !allowed {
+		t.Fatal("first execution refused")
+	}
+	// A run that sent no request gives its execution back.
+	refund()
+	for i := range 2 {
+		if allowed, _, _ := s.AllowExecution(ctx, "ns"); !allowed {
+			t.Fatalf("execution %d refused within the quota", i+1)
+		}
+	}
+	if allowed, _, _ := s.AllowExecution(ctx, "ns"); allowed {
+		t.Error("execution beyond the quota allowed")
+	}
+	if usage.executions != 2 {
+		t.Errorf("counted %d executions; want 2, refused and refunded ones excluded", usage.executions)
+	}
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"time"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+)
+
+type CompletionRepo interface {
+	Insert(ctx context.Context, pc models.PendingCompletion) error
+	Delete(ctx context.Context, id string) (bool, error)
+	Complete(ctx context.Context, id, tokenHash string) (models.PendingCompletion, error)
+	ClaimExpired(ctx context.Context, curUnix helpers.Unix) (models.PendingCompletion, error)
+	List(ctx context.Context, namespace, taskID string) ([]models.PendingCompletion, error)
+}
+
+// CompleteCallback records the result a target reported for a waiting run.
+// The token is single use: once the run is completed, by a callback or its
+// timeout, further callbacks are rejected as not found.
+func (s *SchedulerService) CompleteCallback(ctx context.Context, id, token string, result models.CallbackResult) error {
+	pc, err := s.completionRepo.Complete(ctx, id, helpers.SHA256(token))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.NewError(errors.NotFound, "no waiting run for given callback id and token")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to complete callback: %w", err)
+	}
+
+	if result.Status == models.OutcomeSucceeded {
+		s.logger.Info("Task Completed By Callback", zap.String("taskId", pc.TaskID))
+		s.completeRun(ctx, pc, models.OutcomeSucceeded, "")
+		return nil
+	}
+	msg := result.Message
+	if msg == "" {
+		msg = "failure reported by callback"
+	}
+	s.logger.Warn("Task Failure Reported By Callback", zap.String("taskId", pc.TaskID), zap.String("message", msg))
+	s.completeRun(ctx, pc, models.OutcomeFailed, msg)
+	return nil
+}
+
+// ListWaiting returns the namespace's runs that wait for their result,
+// earliest deadline first. A non-empty taskID limits them to one task.
+func (s *SchedulerService) ListWaiting(ctx context.Context, namespace, taskID string) (*models.PendingCompletionList, error) {
+	waiting, err := s.completionRepo.List(ctx, namespace, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waiting runs: %w", err)
+	}
+	return &models.PendingCompletionList{Waiting: waiting, Count: len(waiting)}, nil
+}
+
+// sweepCallbacks fails waiting runs whose timeout has elapsed, every sweep
+// interval until ctx is done.
+func (s *SchedulerService) sweepCallbacks(ctx context.Context) {
+	defer close(s.sweepDone)
+	ticker := time.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.sweepExpired(ctx)
+	}
+}
+
+func (s *SchedulerService) sweepExpired(ctx context.Context) {
+	for ctx.Err() == nil {
+		pc, err := s.completionRepo.ClaimExpired(ctx, helpers.CurrentUTCUnix())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Failed To Claim Expired Callback", zap.Error(err))
+			}
+			return
+		}
+		msg := fmt.Sprintf("callback not received within %s", time.Duration(pc.TimeoutSeconds)*time.Second)
+		s.logger.Warn("Callback Timed Out, Task Failed", zap.String("taskId", pc.TaskID))
+		s.completeRun(ctx, pc, models.OutcomeFailed, msg)
+	}
+}
+
+// completeRun records the outcome of a waiting run and alerts on failure.
+// The run's record is already removed, so the writes must not be lost to a
+// cancelled ctx.
+func (s *SchedulerService) completeRun(ctx context.Context, pc models.PendingCompletion, outcome models.Outcome, exceptionMsg string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := s.schedulerRepo.UpdateTaskStatus(ctx, pc.TaskID, outcome, exceptionMsg); err != nil {
+		s.logger.Error("Failed To Update Task Status", zap.String("taskId", pc.TaskID), zap.Error(err))
+	}
+	if outcome != models.OutcomeFailed {
+		return
+	}
+	t, err := s.schedulerRepo.GetOne(ctx, pc.Namespace, pc.TaskID)
+	if err != nil {
+		// The task may have been deleted while its run was waiting.
+		s.logger.Warn("Failed To Fetch Task For Alert", zap.String("taskId", pc.TaskID), zap.Error(err))
+		return
+	}
+	if err := s.execDeps.Slack.SendAlert(ctx, t, exceptionMsg); err != nil {
+		s.logger.Error("Error Sending Slack Alert", zap.Error(err))
+	}
+}
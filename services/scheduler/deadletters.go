@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+)
+
+// ListDeadLetters returns the namespace's dead letters, most recent first. A
+// non-empty taskID limits them to one task.
+func (s *SchedulerService) ListDeadLetters(ctx context.Context, namespace, taskID string, limit, skip int64) (*models.DeadLetterList, error) {
+	dls, err := s.deadLetterRepo.List(ctx, namespace, taskID, limit, skip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return &models.DeadLetterList{DeadLetters: dls, Count: len(dls)}, nil
+}
+
+func (s *SchedulerService) GetDeadLetter(ctx context.Context, namespace, id string) (*models.DeadLetter, error) {
+	dl, err := s.deadLetterRepo.GetOne(ctx, namespace, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.NewError(errors.NotFound, "dead letter not found with given id")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dead letter: %w", err)
+	}
+	return &dl, nil
+}
+
+// ReplayDeadLetter executes the dead-lettered request again, with edits
+// applied, as a run of its task, and removes the dead letter. The task must
+// still exist; its current attempts and alerting apply. Edits may reference
+// only the secrets the stored request references. If the replay
+// exhausts its attempts too, it is dead-lettered anew.
+func (s *SchedulerService) ReplayDeadLetter(ctx context.Context, namespace, id string, edit models.ReplayRequest) error {
+	dl, err := s.GetDeadLetter(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+	if err := edit.CheckSecretRefs(dl.Request); err != nil {
+		return err
+	}
+	t, err := s.schedulerRepo.GetOne(ctx, namespace, dl.TaskID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.NewError(errors.Conflict, "task of dead letter no longer exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch task data: %w", err)
+	}
+
+	t.TaskData = edit.Apply(dl.Request)
+	if err := t.TaskData.ValidatePayload(); err != nil {
+		return errors.ValidationFailedErr(err)
+	}
+	if err := s.executeTask(ctx, t, models.TriggerReplay); err != nil {
+		return err
+	}
+	if err := s.deadLetterRepo.Delete(ctx, namespace, id); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		// The replay is under way; a leftover dead letter can still be discarded.
+		s.logger.Error("Failed To Remove Replayed Dead Letter", zap.String("deadLetterId", id), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *SchedulerService) DiscardDeadLetter(ctx context.Context, namespace, id string) error {
+	err := s.deadLetterRepo.Delete(ctx, namespace, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.NewError(errors.NotFound, "dead letter not found with given id")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to discard dead letter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package scheduler
+
+import (
+	// Go Internal Packages
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	// Local Packages
+	models "scheduler/models"
+	executer "scheduler/services/executer"
+
+	// External Packages
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// stragglerTimeout bounds the wait for runs cancelled at the end of the drain
+// period to record their status.
+const stragglerTimeout = 15 * time.Second
+
+// runTimeLayout matches the layout of the other UTC timestamps in the API.
+const runTimeLayout = "2006-01-02T15:04:05.999Z"
+
+// runRegistry tracks the executions in flight on this instance together with
+// the functions that cancel them. It implements executer.RunTracker.
+type runRegistry struct {
+	mu     sync.Mutex
+	runs   map[string]*activeRun
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// activeRun is a single execution in flight. Its mutable fields are guarded
+// by the registry's mutex.
+type activeRun struct {
+	registry    *runRegistry
+	info        models.RunInfo
+	startedAt   time.Time
+	nextAttempt time.Time
+	cancel      context.CancelCauseFunc
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{runs: make(map[string]*activeRun)}
+}
+
+func (r *runRegistry) Begin(ctx context.Context, task models.Task, trigger models.Trigger) (context.Context, executer.RunProgress) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		cancel(executer.ErrShuttingDown)
+		return runCtx, refusedRun{}
+	}
+
+	now := time.Now().UTC()
+	run := &activeRun{
+		registry: r,
+		info: models.RunInfo{
+			RunID:       uuid.New().String(),
+			TaskID:      task.ID,
+			Namespace:   task.Namespace,
+			Trigger:     trigger,
+			StartedAt:   now.Format(runTimeLayout),
+			MaxAttempts: task.NumberOfAttempts,
+		},
+		startedAt: now,
+		cancel:    cancel,
+	}
+	r.runs[run.info.RunID] = run
+	r.wg.Add(1)
+	return runCtx, run
+}
+
+// refusedRun is the progress handle of a run refused because the registry is closed.
+type refusedRun struct{}
+
+func (refusedRun) Attempt(int)       {}
+func (refusedRun) Backoff(time.Time) {}
+func (refusedRun) End()              {}
+
+func (a *activeRun) Attempt(n int) {
+	a.registry.mu.Lock()
+	defer a.registry.mu.Unlock()
+	a.info.Attempt = n
+	a.nextAttempt = time.Time{}
+}
+
+func (a *activeRun) Backoff(until time.Time) {
+	a.registry.mu.Lock()
+	defer a.registry.mu.Unlock()
+	a.nextAttempt = until
+}
+
+func (a *activeRun) End() {
+	a.registry.mu.Lock()
+	delete(a.registry.runs, a.info.RunID)
+	a.registry.mu.Unlock()
+	a.cancel(nil)
+	a.registry.wg.Done()
+}
+
+// close stops the registry from accepting runs and returns how many are still in flight.
+func (r *runRegistry) close() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return len(r.runs)
+}
+
+// wait blocks until every run has ended or ctx is done, reporting whether the
+// runs ended. Call it only after close, so no run can begin while it waits.
+func (r *runRegistry) wait(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// drain closes the registry and waits until ctx is done for the runs in
+// flight to end. Runs still going then are cancelled and recorded as cancelled.
+func (r *runRegistry) drain(ctx context.Context, logger *zap.Logger) {
+	running := r.close()
+	logger.Info("Draining Running Executions", zap.Int("count", running))
+	if r.wait(ctx) {
+		return
+	}
+
+	cancelled := r.cancelAll(executer.ErrShuttingDown)
+	logger.Warn("Drain Period Elapsed, Cancelling Running Executions", zap.Int("count", cancelled))
+
+	// Cancelled runs still record their status, which is bounded by its own timeout.
+	waitCtx, cancel := context.WithTimeout(context.Background(), stragglerTimeout)
+	defer cancel()
+	if !r.wait(waitCtx) {
+		logger.Error("Executions Still Running After Cancellation")
+	}
+}
+
+// cancelAll stops every run with the given cause and returns how many were cancelled.
+func (r *runRegistry) cancelAll(cause error) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, run := range r.runs {
+		run.cancel(cause)
+	}
+	return len(r.runs)
+}
+
+// list returns the runs in flight, oldest first. An empty namespace matches
+// every namespace and an empty taskID every task.
+func (r *runRegistry) list(namespace, taskID string) []models.RunInfo {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matched := []*activeRun{}
+	for _, run := range r.runs {
+		if (namespace != "" && run.info.Namespace != namespace) || (taskID != "" && run.info.TaskID != taskID) {
+			continue
+		}
+		matched = append(matched, run)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].startedAt.Equal(matched[j].startedAt) {
+			return matched[i].startedAt.Before(matched[j].startedAt)
+		}
+		return matched[i].info.RunID < matched[j].info.RunID
+	})
+
+	out := make([]models.RunInfo, 0, len(matched))
+	for _, run := range matched {
+		info := run.info
+		info.RunningSeconds = int64(now.Sub(run.startedAt) / time.Second)
+		if !run.nextAttempt.IsZero() {
+			info.NextAttemptAt = run.nextAttempt.UTC().Format(runTimeLayout)
+		}
+		out = append(out, info)
+	}
+	return out
+}
+
+// cancel stops the run with the given ID in the namespace, reporting whether it was running.
+func (r *runRegistry) cancel(namespace, runID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run, ok := r.runs[runID]
+	if !ok || run.info.Namespace != namespace {
+		return false
+	}
+	run.cancel(executer.ErrRunCancelled)
+	return true
+}
+
+// cancelTask stops every run of the task and returns the IDs of the cancelled runs.
+func (r *runRegistry) cancelTask(namespace, taskID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runIDs := []string{}
+	for id, run := range r.runs {
+		if run.info.Namespace == namespace && run.info.TaskID == taskID {
+			run.cancel(executer.ErrRunCancelled)
+			runIDs = append(runIDs, id)
+		}
+	}
+	sort.Strings(runIDs)
+	return runIDs
+}
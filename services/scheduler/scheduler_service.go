@@ -4,71 +4,142 @@ import (
 	// Go Internal Packages
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	// Local Packages
+	config "scheduler/config"
 	errors "scheduler/errors"
 	models "scheduler/models"
+	auth "scheduler/services/auth"
+	executer "scheduler/services/executer"
 	helpers "scheduler/utils/helpers"
 	httpclient "scheduler/utils/httpclient"
 	notifications "scheduler/utils/notifications"
+	secrets "scheduler/utils/secrets"
 
 	// External Packages
 	"github.com/google/uuid"
-	"github.com/robfig/cron/v3"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.uber.org/zap"
 )
 
 type SchedulerRepo interface {
-	GetOne(ctx context.Context, taskID string) (models.Task, error)
-	GetActive(ctx context.Context, curUnix helpers.Unix) ([]models.Task, error)
+	GetOne(ctx context.Context, namespace, taskID string) (models.Task, error)
+	GetByExternalID(ctx context.Context, namespace, externalID string) (models.Task, error)
+	GetActive(ctx context.Context, namespace string, curUnix helpers.Unix) ([]models.Task, error)
+	List(ctx context.Context, namespace string, selector models.Selector, limit, skip int64) ([]models.Task, error)
 	Insert(ctx context.Context, task models.Task) error
-	UpdateTaskStatus(ctx context.Context, taskID, exceptionMsg string, isComplete bool) error
-	UpdateEnable(ctx context.Context, taskID string, enable bool) (bool, error)
-	Delete(ctx context.Context, taskID string) error
+	InsertMany(ctx context.Context, tasks []models.Task, ordered bool) error
+	Replace(ctx context.Context, task models.Task) error
+	DeleteMany(ctx context.Context, namespace string, taskIDs []string) error
+	UpdateTaskStatus(ctx context.Context, taskID string, outcome models.Outcome, exceptionMsg string) error
+	RetrySkipped(ctx context.Context, taskID string, nextRunAt int64) error
+	UpdateEnable(ctx context.Context, namespace, taskID string, enable bool, nextRunAt int64, updatedBy string) (bool, error)
+	Delete(ctx context.Context, namespace, taskID string) error
+	GetDue(ctx context.Context, curUnix helpers.Unix, limit int64) ([]models.Task, error)
+	ClaimRun(ctx context.Context, taskID string, due, next int64, updatedAt string, claim *models.FireClaim) (bool, error)
+	ReleaseClaim(ctx context.Context, taskID string, fireAt int64) error
+	HeartbeatClaims(ctx context.Context, instance string, curUnix helpers.Unix) error
+	RecoverClaims(ctx context.Context, staleBefore helpers.Unix) (int64, error)
+	GetUnscheduled(ctx context.Context, limit int64) ([]models.Task, error)
+	InitNextRunAt(ctx context.Context, taskID string, nextRunAt int64) error
+}
+
+type DeadLetterRepo interface {
+	Insert(ctx context.Context, dl models.DeadLetter) error
+	List(ctx context.Context, namespace, taskID string, limit, skip int64) ([]models.DeadLetter, error)
+	GetOne(ctx context.Context, namespace, id string) (models.DeadLetter, error)
+	Delete(ctx context.Context, namespace, id string) error
+}
+
+type QuotaService interface {
+	ReserveTasks(ctx context.Context, namespace string, n int) (release func(), err error)
+	AllowExecution(ctx context.Context, namespace string) (allowed bool, refund func(), err error)
 }
 
 type SchedulerService struct {
-	logger        *zap.Logger
-	schedulerRepo SchedulerRepo
-	slack         notifications.Sender
-	client        *httpclient.Client
-	cron          *cron.Cron
-	tasks         map[string]cron.EntryID
-	tasksMu       sync.Mutex
-	timers        map[timerKey]context.CancelFunc
-	timersMu      sync.Mutex
-	execCtx       context.Context
-	execCancel    context.CancelFunc
-}
-
-func NewService(logger *zap.Logger, schedulerRepo SchedulerRepo, slack notifications.Sender, client *httpclient.Client) *SchedulerService {
-	cronObj := cron.New(cron.WithSeconds(), cron.WithLocation(time.UTC))
+	logger         *zap.Logger
+	schedulerRepo  SchedulerRepo
+	deadLetterRepo DeadLetterRepo
+	completionRepo CompletionRepo
+	quota          QuotaService
+	execDeps       *executer.Dependencies
+	runs           *runRegistry
+	queue          JobQueue
+	queued         bool
+	pollInterval   time.Duration
+	claimBatchSize int64
+	sweepInterval  time.Duration
+	instance       string // identifies this process in the claims it holds
+	claimLease     time.Duration
+	dispatchCancel context.CancelFunc
+	dispatchDone   chan struct{}
+	sweepDone      chan struct{}
+	claimsCancel   context.CancelFunc
+	claimsDone     chan struct{}
+	execCtx        context.Context
+	execCancel     context.CancelFunc
+}
+
+func NewService(logger *zap.Logger, schedulerRepo SchedulerRepo, slack notifications.Sender, client *httpclient.Client, secrets *secrets.Resolver, quota QuotaService, pool *executer.Pool, limiter *executer.RateLimiter, breaker *executer.Breaker, deadLetterRepo DeadLetterRepo, completionRepo CompletionRepo, callbackURL string, queue JobQueue, cfg config.Scheduler) *SchedulerService {
 	execCtx, execCancel := context.WithCancel(context.Background())
+	runs := newRunRegistry()
 	return &SchedulerService{
-		logger:        logger,
-		schedulerRepo: schedulerRepo,
-		slack:         slack,
-		client:        client,
-		cron:          cronObj,
-		tasks:         make(map[string]cron.EntryID),
-		timers:        make(map[timerKey]context.CancelFunc),
-		execCtx:       execCtx,
-		execCancel:    execCancel,
-	}
-}
-
-// Stop halts the cron runner and cancels all in-flight executor goroutines.
-// Call this before closing the database connection.
-func (s *SchedulerService) Stop() {
-	s.cron.Stop()
+		logger:         logger,
+		schedulerRepo:  schedulerRepo,
+		deadLetterRepo: deadLetterRepo,
+		completionRepo: completionRepo,
+		quota:          quota,
+		execDeps: &executer.Dependencies{
+			Logger:      logger,
+			Repo:        schedulerRepo,
+			Slack:       slack,
+			Client:      client,
+			Secrets:     secrets,
+			Quota:       quota,
+			Runs:        runs,
+			Pool:        pool,
+			Limiter:     limiter,
+			Breaker:     breaker,
+			DeadLetters: deadLetterRepo,
+			Completions: completionRepo,
+			CallbackURL: callbackURL,
+		},
+		runs:           runs,
+		queue:          queue,
+		queued:         cfg.ExecutionMode == config.ExecutionQueue,
+		pollInterval:   cfg.PollInterval,
+		claimBatchSize: int64(cfg.ClaimBatchSize),
+		sweepInterval:  cfg.CallbackSweepInterval,
+		instance:       uuid.New().String(),
+		claimLease:     cfg.ClaimLease,
+		execCtx:        execCtx,
+		execCancel:     execCancel,
+	}
+}
+
+// Stop drains the scheduler: no new fires start, and executions in flight are
+// given until ctx is done to finish. Stragglers are then cancelled and recorded
+// as cancelled. Call this before closing the database connection.
+func (s *SchedulerService) Stop(ctx context.Context) {
+	// The dispatcher exits before the registry closes, so no claimed fire is refused.
+	if s.dispatchCancel != nil {
+		s.dispatchCancel()
+		<-s.dispatchDone
+		<-s.sweepDone
+	}
+	s.runs.drain(ctx, s.logger)
+	// Claims are kept alive until the drained runs have released them.
+	if s.claimsCancel != nil {
+		s.claimsCancel()
+		<-s.claimsDone
+	}
 	s.execCancel()
+	s.logger.Info("Scheduler Stopped")
 }
 
-func (s *SchedulerService) GetOne(ctx context.Context, taskID string) (*models.Task, error) {
-	t, err := s.schedulerRepo.GetOne(ctx, taskID)
+func (s *SchedulerService) GetOne(ctx context.Context, namespace, taskID string) (*models.Task, error) {
+	t, err := s.schedulerRepo.GetOne(ctx, namespace, taskID)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, errors.NewError(errors.NotFound, "task not found with given id")
 	}
@@ -78,9 +149,9 @@ func (s *SchedulerService) GetOne(ctx context.Context, taskID string) (*models.T
 	return &t, nil
 }
 
-func (s *SchedulerService) GetActive(ctx context.Context) (*models.ActiveList, error) {
+func (s *SchedulerService) GetActive(ctx context.Context, namespace string) (*models.ActiveList, error) {
 	curUnix := helpers.CurrentUTCUnix()
-	tasks, err := s.schedulerRepo.GetActive(ctx, curUnix)
+	tasks, err := s.schedulerRepo.GetActive(ctx, namespace, curUnix)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch active tasks: %w", err)
 	}
@@ -92,39 +163,295 @@ func (s *SchedulerService) GetActive(ctx context.Context) (*models.ActiveList, e
 	return &models.ActiveList{ActiveTasks: out}, nil
 }
 
-func (s *SchedulerService) Insert(ctx context.Context, taskQP models.CreateRequest) (string, error) {
-	taskID := uuid.New().String()
+func (s *SchedulerService) List(ctx context.Context, namespace string, selector models.Selector, limit, skip int64) (*models.TaskList, error) {
+	tasks, err := s.schedulerRepo.List(ctx, namespace, selector, limit, skip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return &models.TaskList{Tasks: tasks, Count: len(tasks)}, nil
+}
+
+// Agenda groups the fires expected in [from, to) by hour. Fires before now
+// are not expected any more, so the window is clamped to start now at the
+// earliest. An empty namespace covers every namespace.
+func (s *SchedulerService) Agenda(ctx context.Context, namespace string, from, to time.Time) (*models.Agenda, error) {
+	curUnix := helpers.CurrentUTCUnix()
+	fromUnix := max(helpers.Unix(from.Unix()), curUnix)
+	tasks, err := s.schedulerRepo.GetActive(ctx, namespace, curUnix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch active tasks: %w", err)
+	}
+	return models.BuildAgenda(tasks, namespace, curUnix, fromUnix, helpers.Unix(to.Unix())), nil
+}
+
+// Preview lists the next n fire times of a stored task.
+func (s *SchedulerService) Preview(ctx context.Context, namespace, taskID string, n int) (*models.Preview, error) {
+	t, err := s.GetOne(ctx, namespace, taskID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := t.PreviewFireTimes(helpers.CurrentUTCUnix(), n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview task: %w", err)
+	}
+	return p, nil
+}
+
+// PreviewRequest lists the next n fire times the task would have if it were created now.
+func (s *SchedulerService) PreviewRequest(_ context.Context, namespace string, taskQP models.CreateRequest, n int) (*models.Preview, error) {
+	t, err := taskQP.ToTask("", namespace, helpers.GetCurrentDateTime())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build task: %w", err)
+	}
+	p, err := t.PreviewFireTimes(helpers.CurrentUTCUnix(), n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview task: %w", err)
+	}
+	return p, nil
+}
+
+// Insert creates and schedules a task. When the request carries an external
+// ID that was already used in the namespace, the original task is returned with
+// replayed set if the payloads match, and a Conflict error otherwise.
+func (s *SchedulerService) Insert(ctx context.Context, namespace string, taskQP models.CreateRequest) (task *models.Task, replayed bool, err error) {
+	if taskQP.ExternalID != "" {
+		if existing, err := s.replay(ctx, namespace, taskQP); existing != nil || err != nil {
+			return existing, err == nil, err
+		}
+	}
+	release, err := s.quota.ReserveTasks(ctx, namespace, 1)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
 	curTime := helpers.GetCurrentDateTime()
-	t, err := taskQP.ToTask(taskID, curTime)
+	t, err := taskQP.ToTask(uuid.New().String(), namespace, curTime)
 	if err != nil {
-		return "", fmt.Errorf("failed to build task: %w", err)
+		return nil, false, fmt.Errorf("failed to build task: %w", err)
 	}
+	t.CreatedBy = auth.Subject(ctx)
+	t.UpdatedBy = t.CreatedBy
+	t.ScheduleNext(helpers.CurrentUTCUnix())
 	if err := s.schedulerRepo.Insert(ctx, t); err != nil {
-		return "", fmt.Errorf("failed to insert task: %w", err)
+		// A concurrent request with the same external ID won the race.
+		if taskQP.ExternalID != "" && mongo.IsDuplicateKeyError(err) {
+			if existing, err := s.replay(ctx, namespace, taskQP); existing != nil || err != nil {
+				return existing, err == nil, err
+			}
+		}
+		return nil, false, fmt.Errorf("failed to insert task: %w", err)
 	}
-	s.scheduleTask(t)
-	return t.ID, nil
+	return &t, false, nil
 }
 
-func (s *SchedulerService) Delete(ctx context.Context, taskID string) error {
-	err := s.schedulerRepo.Delete(ctx, taskID)
+// replay returns the task previously created with the request's external ID,
+// or nil when there is none.
+func (s *SchedulerService) replay(ctx context.Context, namespace string, taskQP models.CreateRequest) (*models.Task, error) {
+	existing, err := s.schedulerRepo.GetByExternalID(ctx, namespace, taskQP.ExternalID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch task by external id: %w", err)
+	}
+	if existing.RequestHash != taskQP.RequestHash {
+		return nil, errors.NewError(errors.Conflict, "external id already used with a different payload")
+	}
+	return &existing, nil
+}
+
+// InsertBatch creates the validated items with one bulk write and schedules
+// those that were stored. In atomic mode any failure removes the whole batch.
+func (s *SchedulerService) InsertBatch(ctx context.Context, namespace string, mode models.BatchMode, items []models.BatchItem) ([]models.BatchItemResult, error) {
+	release, err := s.quota.ReserveTasks(ctx, namespace, len(items))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	curTime := helpers.GetCurrentDateTime()
+	actor := auth.Subject(ctx)
+	results := make([]models.BatchItemResult, len(items))
+	tasks := make([]models.Task, 0, len(items))
+	taskItems := make([]int, 0, len(items)) // position in items of each entry in tasks
+	for i, item := range items {
+		results[i].Index = item.Index
+		t, err := item.Request.ToTask(uuid.New().String(), namespace, curTime)
+		if err != nil {
+			if mode == models.BatchAtomic {
+				return nil, fmt.Errorf("failed to build task %d: %w", item.Index, err)
+			}
+			results[i].Error = "failed to build task"
+			continue
+		}
+		t.CreatedBy = actor
+		t.UpdatedBy = actor
+		tasks = append(tasks, t)
+		taskItems = append(taskItems, i)
+	}
+
+	failed, err := s.insertTasks(ctx, namespace, mode, tasks)
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range tasks {
+		res := &results[taskItems[i]]
+		if msg, ok := failed[i]; ok {
+			res.Error = msg
+			continue
+		}
+		res.TaskID = t.ID
+	}
+	return results, nil
+}
+
+// Import stores exported tasks in the namespace. Tasks that were prepared and
+// validated with the import options keep their schedule and status; IDs are
+// regenerated unless opts.PreserveIDs is set.
+func (s *SchedulerService) Import(ctx context.Context, namespace string, tasks []models.Task, opts models.ImportOptions) ([]models.BatchItemResult, error) {
+	release, err := s.quota.ReserveTasks(ctx, namespace, len(tasks))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	curTime := helpers.GetCurrentDateTime()
+	actor := auth.Subject(ctx)
+	for i := range tasks {
+		t := &tasks[i]
+		t.Namespace = namespace
+		if !opts.PreserveIDs {
+			t.ID = uuid.New().String()
+			t.CreatedAt = curTime
+			t.CreatedBy = actor
+		}
+		t.UpdatedAt = curTime
+		t.UpdatedBy = actor
+		t.Claim = nil // held by a run of the exporting instance
+	}
+
+	failed, err := s.insertTasks(ctx, namespace, opts.Mode, tasks)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]models.BatchItemResult, len(tasks))
+	for i, t := range tasks {
+		results[i].Index = i
+		if msg, ok := failed[i]; ok {
+			results[i].Error = msg
+			continue
+		}
+		results[i].TaskID = t.ID
+	}
+	return results, nil
+}
+
+// insertTasks stores tasks with one bulk write and schedules the enabled ones
+// that have not expired. In best-effort mode it returns an error message per
+// failed task index; in atomic mode any failure rolls back the tasks inserted
+// so far and is returned as an error.
+func (s *SchedulerService) insertTasks(ctx context.Context, namespace string, mode models.BatchMode, tasks []models.Task) (map[int]string, error) {
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	curUnix := helpers.CurrentUTCUnix()
+	for i := range tasks {
+		tasks[i].ScheduleNext(curUnix)
+	}
+
+	failed := make(map[int]string)
+	if err := s.schedulerRepo.InsertMany(ctx, tasks, mode == models.BatchAtomic); err != nil {
+		var bwe mongo.BulkWriteException
+		isBulkErr := errors.As(err, &bwe) && bwe.WriteConcernError == nil && len(bwe.WriteErrors) > 0
+		if mode == models.BatchAtomic || !isBulkErr {
+			// An ordered insert stops at the first write error, so only the
+			// tasks before it were stored. Never touch the conflicting ones.
+			inserted := tasks
+			if isBulkErr {
+				inserted = tasks[:bwe.WriteErrors[0].Index]
+			}
+			ids := make([]string, len(inserted))
+			for i, t := range inserted {
+				ids[i] = t.ID
+			}
+			if len(ids) > 0 {
+				if delErr := s.schedulerRepo.DeleteMany(ctx, namespace, ids); delErr != nil {
+					s.logger.Error("Failed To Roll Back Batch Insert", zap.Error(delErr))
+				}
+			}
+			if isBulkErr && mongo.IsDuplicateKeyError(bwe.WriteErrors[0]) {
+				return nil, errors.NewError(errors.Conflict,
+					fmt.Sprintf("task %d already exists", bwe.WriteErrors[0].Index))
+			}
+			return nil, fmt.Errorf("failed to insert tasks: %w", err)
+		}
+		for _, we := range bwe.WriteErrors {
+			failed[we.Index] = "failed to insert task"
+			if mongo.IsDuplicateKeyError(we) {
+				failed[we.Index] = "task already exists"
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+// Update replaces the definition of an existing task and reschedules it. When
+// the start time changes the run status is reset, so an updated one-off task
+// runs again at its new time; otherwise a one-off task that ran stays done.
+func (s *SchedulerService) Update(ctx context.Context, namespace, taskID string, taskQP models.CreateRequest) error {
+	existing, err := s.GetOne(ctx, namespace, taskID)
+	if err != nil {
+		return err
+	}
+
+	t, err := taskQP.ToTask(taskID, namespace, helpers.GetCurrentDateTime())
+	if err != nil {
+		return fmt.Errorf("failed to build task: %w", err)
+	}
+	t.ExternalID = existing.ExternalID
+	t.RequestHash = existing.RequestHash
+	t.CreatedAt = existing.CreatedAt
+	t.CreatedBy = existing.CreatedBy
+	t.UpdatedBy = auth.Subject(ctx)
+	if t.StartUnix == existing.StartUnix {
+		t.Status = existing.Status
+	}
+	t.ScheduleNext(helpers.CurrentUTCUnix())
+	err = s.schedulerRepo.Replace(ctx, t)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.NewError(errors.NotFound, "task not found with given id")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	return nil
+}
+
+func (s *SchedulerService) Delete(ctx context.Context, namespace, taskID string) error {
+	err := s.schedulerRepo.Delete(ctx, namespace, taskID)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return errors.NewError(errors.NotFound, "task not found with given id")
 	}
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
-	s.discardTaskNow(taskID)
 	return nil
 }
 
-func (s *SchedulerService) Enable(ctx context.Context, taskID string) error {
-	t, err := s.GetOne(ctx, taskID)
+// Enable enables the task and schedules its next fire. Expired tasks and
+// non-recurring tasks that already ran are enabled without a pending fire.
+func (s *SchedulerService) Enable(ctx context.Context, namespace, taskID string) error {
+	t, err := s.GetOne(ctx, namespace, taskID)
 	if err != nil {
 		return err
 	}
 
-	updated, err := s.schedulerRepo.UpdateEnable(ctx, taskID, true)
+	t.Enable = true
+	t.ScheduleNext(helpers.CurrentUTCUnix())
+	updated, err := s.schedulerRepo.UpdateEnable(ctx, namespace, taskID, true, t.NextRunAt, auth.Subject(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update enable status: %w", err)
 	}
@@ -132,44 +459,73 @@ func (s *SchedulerService) Enable(ctx context.Context, taskID string) error {
 		s.logger.Info("Task Is Already Enabled", zap.String("taskId", taskID))
 		return nil
 	}
-
-	alreadyExecuted := t.Status.IsAlreadyExecuted()
-	if alreadyExecuted && !t.IsRecurEnabled {
-		s.logger.Info("Non Recurring Task Already Executed, Skipping Reschedule",
-			zap.String("taskId", taskID))
-		return nil
-	}
-
-	curUnix := helpers.CurrentUTCUnix()
-	if curUnix > helpers.Unix(t.EndUnix) {
-		s.logger.Info("Task Already Expired", zap.String("taskId", taskID))
-		return nil
+	if t.NextRunAt == 0 {
+		s.logger.Info("Task Has No Upcoming Fire, Enabled Without Schedule", zap.String("taskId", taskID))
 	}
-
-	s.scheduleTask(*t)
 	return nil
 }
 
-func (s *SchedulerService) Disable(ctx context.Context, taskID string) error {
-	if _, err := s.GetOne(ctx, taskID); err != nil {
+func (s *SchedulerService) Disable(ctx context.Context, namespace, taskID string) error {
+	if _, err := s.GetOne(ctx, namespace, taskID); err != nil {
 		return err
 	}
 
-	updated, err := s.schedulerRepo.UpdateEnable(ctx, taskID, false)
+	updated, err := s.schedulerRepo.UpdateEnable(ctx, namespace, taskID, false, 0, auth.Subject(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update enable status: %w", err)
 	}
 	if !updated {
 		s.logger.Info("Task Is Already Disabled", zap.String("taskId", taskID))
-		return nil
 	}
+	return nil
+}
+
+// PoolStats reports the usage of this instance's execution pool.
+func (s *SchedulerService) PoolStats(_ context.Context) (*models.PoolStats, error) {
+	stats := s.execDeps.Pool.Stats()
+	return &stats, nil
+}
+
+// errRunsOnWorkers is returned by the run endpoints in queue mode, where runs
+// execute in worker processes that this instance cannot see or cancel.
+var errRunsOnWorkers = errors.NewError(errors.Invalid, "runs execute on workers in queue mode and cannot be listed or cancelled through the API")
+
+// ListRuns returns the runs in flight on this instance, optionally only those of one task.
+func (s *SchedulerService) ListRuns(_ context.Context, namespace, taskID string) (*models.RunList, error) {
+	if s.queued {
+		return nil, errRunsOnWorkers
+	}
+	runs := s.runs.list(namespace, taskID)
+	return &models.RunList{Runs: runs, Count: len(runs)}, nil
+}
 
-	s.discardTaskNow(taskID)
+// CancelRun cancels a single in-flight run. The run is recorded as cancelled and does not alert.
+func (s *SchedulerService) CancelRun(_ context.Context, namespace, runID string) error {
+	if s.queued {
+		return errRunsOnWorkers
+	}
+	if !s.runs.cancel(namespace, runID) {
+		return errors.NewError(errors.NotFound, "no running execution found with given id")
+	}
+	s.logger.Info("Cancelled Running Execution", zap.String("runId", runID))
 	return nil
 }
 
-func (s *SchedulerService) ExecuteNow(ctx context.Context, taskID string) error {
-	t, err := s.GetOne(ctx, taskID)
+// CancelTaskRuns cancels every in-flight run of the task and returns the cancelled run IDs.
+func (s *SchedulerService) CancelTaskRuns(ctx context.Context, namespace, taskID string) ([]string, error) {
+	if s.queued {
+		return nil, errRunsOnWorkers
+	}
+	if _, err := s.GetOne(ctx, namespace, taskID); err != nil {
+		return nil, err
+	}
+	runIDs := s.runs.cancelTask(namespace, taskID)
+	s.logger.Info("Cancelled Running Executions Of Task", zap.String("taskId", taskID), zap.Int("count", len(runIDs)))
+	return runIDs, nil
+}
+
+func (s *SchedulerService) ExecuteNow(ctx context.Context, namespace, taskID string) error {
+	t, err := s.GetOne(ctx, namespace, taskID)
 	if err != nil {
 		return err
 	}
@@ -180,6 +536,55 @@ func (s *SchedulerService) ExecuteNow(ctx context.Context, taskID string) error
 		return nil
 	}
 
-	s.executeTaskNow(*t)
-	return nil
+	return s.executeTask(ctx, *t, models.TriggerManual)
+}
+
+// Bulk applies action to every task in the namespace matching selector, using
+// the same semantics as the single-task operations, and reports each outcome.
+func (s *SchedulerService) Bulk(ctx context.Context, namespace string, action models.BulkAction, selector models.Selector) (*models.BulkResult, error) {
+	var apply func(ctx context.Context, namespace, taskID string) error
+	switch action {
+	case models.BulkEnable:
+		apply = s.Enable
+	case models.BulkDisable:
+		apply = s.Disable
+	case models.BulkDelete:
+		apply = s.Delete
+	case models.BulkExecute:
+		apply = s.ExecuteNow
+	default:
+		return nil, errors.NewError(errors.Invalid, fmt.Sprintf("unknown bulk action: %s", action))
+	}
+
+	tasks, err := s.schedulerRepo.List(ctx, namespace, selector, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	res := &models.BulkResult{
+		Action:   action,
+		Selector: selector.String(),
+		Matched:  len(tasks),
+		Results:  make([]models.BulkItemResult, 0, len(tasks)),
+	}
+	for _, t := range tasks {
+		item := models.BulkItemResult{TaskID: t.ID, OK: true}
+		if err := apply(ctx, namespace, t.ID); err != nil {
+			item.OK = false
+			var typedErr *errors.Error
+			if errors.As(err, &typedErr) {
+				item.Error = typedErr.Message
+			} else {
+				s.logger.Error("Bulk Action Failed", zap.String("taskId", t.ID), zap.String("action", string(action)), zap.Error(err))
+				item.Error = "internal error"
+			}
+		}
+		if item.OK {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+		res.Results = append(res.Results, item)
+	}
+	return res, nil
 }
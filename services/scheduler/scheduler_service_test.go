@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+)
+
+// batchRepo fails InsertMany with err and records the IDs rolled back.
+type batchRepo struct {
+	SchedulerRepo
+	err      error
+	inserted []string
+	deleted  [][]string
+}
+
+func (r *batchRepo) InsertMany(_ context.Context, tasks []models.Task, _ bool) error {
+	for _, t := range tasks {
+		r.inserted = append(r.inserted, t.ID)
+	}
+	return r.err
+}
+
+func (r *batchRepo) DeleteMany(_ context.Context, _ string, ids []string) error {
+	r.deleted = append(r.deleted, ids)
+	return nil
+}
+
+// noQuota admits every task.
+type noQuota struct{ QuotaService }
+
+func (noQuota) ReserveTasks(context.Context, string, int) (func(), error) { return func() {}, nil }
+
+func writeErrorAt(index int) error {
+	return mongo.BulkWriteException{
+		WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: index, Code: 11000, Message: "duplicate key"}}},
+	}
+}
+
+func TestInsertBatchAtomicRollback(t *testing.T) {
+	items := make([]models.BatchItem, 4)
+	for i := range items {
+		items[i] = models.BatchItem{Index: i, Request: models.CreateRequest{
+			ScheduleDate: "2030-01-01",
+			ScheduleTime: "10:00",
+			ExpiresAt:    "2030-01-02T00:00:00.000Z",
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		err         error
+		wantDeleted func(ids []string) [][]string
+	}{
+		{"write error keeps the conflicting task", writeErrorAt(2), func(ids []string) [][]string { return [][]string{ids[:2]} }},
+		{"first task failed", writeErrorAt(0), func([]string) [][]string { return nil }},
+		{"other error removes every task", fmt.Errorf("connection reset"), func(ids []string) [][]string { return [][]string{ids} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &batchRepo{err: tt.err}
+			s := &SchedulerService{logger: zap.NewNop(), schedulerRepo: repo, quota: noQuota{}}
+			if _, err := s.InsertBatch(context.Background(), "default", models.BatchAtomic, items); err == nil {
+				t.Fatal("InsertBatch() error = nil; want the insert failure")
+			}
+			if want := tt.wantDeleted(repo.inserted); !slices.EqualFunc(repo.deleted, want, slices.Equal) {
+				t.Errorf("rolled back %v; want %v", repo.deleted, want)
+			}
+		})
+	}
+}
+
+func TestRunEndpointsInQueueMode(t *testing.T) {
+	s := newTestService(&fakeRepo{}, &fakeQueue{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"ListRuns", func() error { _, err := s.ListRuns(ctx, "default", ""); return err }},
+		{"CancelRun", func() error { return s.CancelRun(ctx, "default", "run-1") }},
+		{"CancelTaskRuns", func() error { _, err := s.CancelTaskRuns(ctx, "default", "t1"); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e *errors.Error
+			if err := tt.call(); !errors.As(err, &e) || e.Kind != errors.Invalid {
+				t.Errorf("%s() error = %v; want an Invalid error", tt.name, err)
+			}
+		})
+	}
+}
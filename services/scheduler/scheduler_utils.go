@@ -7,210 +7,231 @@ import (
 	"time"
 
 	// Local Packages
+	errors "scheduler/errors"
 	models "scheduler/models"
 	executer "scheduler/services/executer"
 	helpers "scheduler/utils/helpers"
 
 	// External Packages
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-type timerKey struct {
-	kind   string
-	taskID string
-}
-
-// Start schedules all active tasks from the database and starts the cron runner.
+// Start sets the next run time of tasks stored before it was persisted, gives
+// back the fires of runs lost with a previous process and starts the
+// dispatcher. Fire times live in the database, so a restart picks up exactly
+// where the previous process stopped.
 func (s *SchedulerService) Start(ctx context.Context) error {
-	curUnix := helpers.CurrentUTCUnix()
-	tasks, err := s.schedulerRepo.GetActive(ctx, curUnix)
+	count, err := s.backfillNextRunAt(ctx)
 	if err != nil {
-		return fmt.Errorf("unable to fetch tasks: %w", err)
+		return fmt.Errorf("unable to schedule stored tasks: %w", err)
 	}
-
-	for _, t := range tasks {
-		s.scheduleTask(t)
+	recovered, err := s.schedulerRepo.RecoverClaims(ctx, helpers.CurrentUTCUnix()-helpers.Unix(s.claimLease.Seconds()))
+	if err != nil {
+		return fmt.Errorf("unable to recover claimed fires: %w", err)
 	}
 
-	s.cron.Start()
-	s.logger.Info("Successfully Scheduled All Tasks", zap.Int("count", len(tasks)))
+	claimsCtx, claimsCancel := context.WithCancel(context.Background())
+	s.claimsCancel = claimsCancel
+	s.claimsDone = make(chan struct{})
+	go s.keepClaims(claimsCtx)
+
+	dispatchCtx, cancel := context.WithCancel(context.Background())
+	s.dispatchCancel = cancel
+	s.dispatchDone = make(chan struct{})
+	s.sweepDone = make(chan struct{})
+	go s.dispatch(dispatchCtx)
+	go s.sweepCallbacks(dispatchCtx)
+
+	s.logger.Info("Successfully Started Dispatcher",
+		zap.Int("backfilled", count), zap.Int64("recovered", recovered), zap.Duration("pollInterval", s.pollInterval))
 	return nil
 }
 
-// scheduleTask routes the task to the correct scheduling path based on start time.
-func (s *SchedulerService) scheduleTask(t models.Task) {
-	curUnix := helpers.CurrentUTCUnix()
-	startUnix := helpers.Unix(t.StartUnix)
-
-	switch {
-	case curUnix == startUnix:
-		s.scheduleTaskNow(t)
-	case curUnix < startUnix:
-		// Register cancel before the goroutine starts to eliminate the race window
-		// where DiscardTaskNow could run before the goroutine registers itself.
-		ctx, cancel := context.WithCancel(context.Background())
-		key := timerKey{kind: "schedule", taskID: t.ID}
-		s.timersMu.Lock()
-		s.timers[key] = cancel
-		s.timersMu.Unlock()
-		go s.scheduleTaskWithDelay(ctx, startUnix.DurationFrom(curUnix), t)
-	default:
-		s.scheduleExistingTask(t)
+// keepClaims refreshes the claims of this instance's runs and gives back the
+// fires of claims that other instances stopped refreshing, several times per
+// claim lease until ctx is done.
+func (s *SchedulerService) keepClaims(ctx context.Context) {
+	defer close(s.claimsDone)
+	ticker := time.NewTicker(s.claimLease / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		curUnix := helpers.CurrentUTCUnix()
+		if err := s.schedulerRepo.HeartbeatClaims(ctx, s.instance, curUnix); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed To Refresh Claims", zap.Error(err))
+		}
+		recovered, err := s.schedulerRepo.RecoverClaims(ctx, curUnix-helpers.Unix(s.claimLease.Seconds()))
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Failed To Recover Claimed Fires", zap.Error(err))
+			}
+			continue
+		}
+		if recovered > 0 {
+			s.logger.Warn("Recovered Fires Of Lost Runs", zap.Int64("count", recovered))
+		}
 	}
 }
 
-// scheduleTaskNow adds the task to cron and fires it immediately.
-// Cron/v3 does not support immediate first-fire, so the first execution is triggered manually.
-// tasksMu is held across the duplicate-check, AddJob, and map-write to prevent concurrent
-// calls from double-scheduling the same task and leaking cron entries.
-func (s *SchedulerService) scheduleTaskNow(t models.Task) {
-	executor := executer.NewExecutorService(s.execCtx, s.logger, t, s.schedulerRepo, s.slack, s.client)
-
-	s.tasksMu.Lock()
-	if _, exists := s.tasks[t.ID]; exists {
-		s.tasksMu.Unlock()
-		s.logger.Error("Task Is Already Scheduled", zap.String("taskId", t.ID))
-		return
-	}
-
-	go executor.Run()
-
-	if !t.IsRecurEnabled {
-		s.tasksMu.Unlock()
-		s.logger.Info("Non Recurring Task, Not Added To Cron", zap.String("taskId", t.ID))
-		return
+// backfillNextRunAt computes nextRunAt for tasks that have none, in batches,
+// and returns how many it set.
+func (s *SchedulerService) backfillNextRunAt(ctx context.Context) (int, error) {
+	curUnix := helpers.CurrentUTCUnix()
+	count := 0
+	for {
+		tasks, err := s.schedulerRepo.GetUnscheduled(ctx, s.claimBatchSize)
+		if err != nil {
+			return count, err
+		}
+		for _, t := range tasks {
+			t.ScheduleNext(curUnix)
+			if err := s.schedulerRepo.InitNextRunAt(ctx, t.ID, t.NextRunAt); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if int64(len(tasks)) < s.claimBatchSize {
+			return count, nil
+		}
 	}
+}
 
-	entryID, err := s.cron.AddJob(fmt.Sprintf("@every %ds", t.Recur), executor)
-	if err != nil {
-		s.tasksMu.Unlock()
-		s.logger.Error("Unable To Schedule Task", zap.String("taskId", t.ID), zap.Error(err))
-		return
+// dispatch polls for due tasks every poll interval until ctx is done.
+func (s *SchedulerService) dispatch(ctx context.Context) {
+	defer close(s.dispatchDone)
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-	s.tasks[t.ID] = entryID
-	s.tasksMu.Unlock()
-
-	endUnix := helpers.Unix(t.EndUnix)
-	curUnix := helpers.CurrentUTCUnix()
-	deletesIn := endUnix.DurationFrom(curUnix) + time.Second
-
-	ctx, cancel := context.WithCancel(context.Background())
-	key := timerKey{kind: "discard", taskID: t.ID}
-	s.timersMu.Lock()
-	s.timers[key] = cancel
-	s.timersMu.Unlock()
-	go s.discardTaskWithDelay(ctx, deletesIn, t.ID)
 }
 
-// scheduleTaskWithDelay calls scheduleTaskNow after the given duration.
-// The context is pre-registered by the caller before this goroutine starts.
-func (s *SchedulerService) scheduleTaskWithDelay(ctx context.Context, duration time.Duration, t models.Task) {
-	key := timerKey{kind: "schedule", taskID: t.ID}
-	defer func() {
-		s.timersMu.Lock()
-		delete(s.timers, key)
-		s.timersMu.Unlock()
-	}()
-
-	timer := time.NewTimer(duration)
-	defer timer.Stop()
-	s.logger.Info("Starting Task With Delay", zap.String("taskId", t.ID), zap.Duration("delay", duration))
-
-	select {
-	case <-timer.C:
-		s.scheduleTaskNow(t)
-	case <-ctx.Done():
-		s.logger.Info("Cancelled Pending Schedule Timer", zap.String("taskId", t.ID))
+// dispatchDue claims and starts every task that is due, a batch at a time,
+// earliest first. A full batch is followed by another query right away.
+func (s *SchedulerService) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		curUnix := helpers.CurrentUTCUnix()
+		tasks, err := s.schedulerRepo.GetDue(ctx, curUnix, s.claimBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Failed To Fetch Due Tasks", zap.Error(err))
+			}
+			return
+		}
+
+		claimed := 0
+		for _, t := range tasks {
+			ok, err := s.claim(ctx, t, curUnix)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Error("Failed To Claim Task", zap.String("taskId", t.ID), zap.Error(err))
+				}
+				return
+			}
+			if ok {
+				claimed++
+			}
+		}
+		if int64(len(tasks)) < s.claimBatchSize || claimed == 0 {
+			return
+		}
 	}
 }
 
-// scheduleExistingTask handles tasks whose start time has already passed.
-// For non-recurring missed tasks it fires immediately; for recurring tasks it
-// calculates the next interval and defers.
-func (s *SchedulerService) scheduleExistingTask(t models.Task) {
-	if !t.IsRecurEnabled && t.Status.IsAlreadyExecuted() {
-		s.logger.Info("Non Recurring Task Already Executed, Skipping", zap.String("taskId", t.ID))
-		return
+// claim advances the task's next run past curUnix and, if this instance won
+// the claim, starts the due fire or, in queue mode, enqueues it for a worker.
+// A task changed since it was fetched is left for the next query, which sees
+// its new definition. A fire run in process is recorded with the claim until
+// the run ends, so that it is given back if this process dies meanwhile.
+func (s *SchedulerService) claim(ctx context.Context, t models.Task, curUnix helpers.Unix) (bool, error) {
+	due := t.NextRunAt
+	next := t.NextRunAfterFire(curUnix)
+	if !s.queued {
+		t.Claim = &models.FireClaim{FireAt: due, Instance: s.instance, HeartbeatAt: int64(curUnix)}
 	}
-	if !t.IsRecurEnabled && t.Recur == 0 {
-		s.logger.Info("Executing Non Recurring Missed Task", zap.String("taskId", t.ID))
-		s.scheduleTaskNow(t)
-		return
+	ok, err := s.schedulerRepo.ClaimRun(ctx, t.ID, due, next, t.UpdatedAt, t.Claim)
+	if err != nil || !ok {
+		return false, err
 	}
 
-	startUnix := helpers.Unix(t.StartUnix)
-	endUnix := helpers.Unix(t.EndUnix)
-	curUnix := helpers.CurrentUTCUnix()
-
-	intervalInSeconds := int64(t.Recur)
-	if intervalInSeconds == 0 {
-		s.logger.Error("Recurring task has zero recur interval, skipping", zap.String("taskId", t.ID))
-		return
+	s.logger.Info("Dispatching Task",
+		zap.String("taskId", t.ID),
+		zap.Duration("delay", curUnix.DurationFrom(helpers.Unix(due))),
+		zap.Int64("nextRunAt", next),
+	)
+	if !s.queued {
+		if !s.newExecutor(t, models.TriggerSchedule).Start() {
+			s.restoreFire(ctx, t, due, next)
+			return false, nil
+		}
+		return true, nil
 	}
-	nextTriggerIn := time.Duration(intervalInSeconds-(int64(curUnix-startUnix)%intervalInSeconds)) * time.Second
-	endDurationIn := endUnix.DurationFrom(curUnix)
-	if nextTriggerIn > endDurationIn {
-		return
+
+	// The job ID is derived from the fire, so enqueueing it twice is harmless.
+	job := newJob(fmt.Sprintf("%s@%d", t.ID, due), t, models.TriggerSchedule, due)
+	if err := s.queue.Enqueue(ctx, job); err != nil {
+		s.restoreFire(ctx, t, due, next)
+		return false, fmt.Errorf("failed to enqueue job: %w", err)
 	}
+	return true, nil
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	key := timerKey{kind: "schedule", taskID: t.ID}
-	s.timersMu.Lock()
-	s.timers[key] = cancel
-	s.timersMu.Unlock()
-	go s.scheduleTaskWithDelay(ctx, nextTriggerIn, t)
+// restoreFire gives a claimed fire that was not started back, so that the
+// next poll retries it.
+func (s *SchedulerService) restoreFire(ctx context.Context, t models.Task, due, next int64) {
+	if _, err := s.schedulerRepo.ClaimRun(ctx, t.ID, next, due, t.UpdatedAt, nil); err != nil {
+		s.logger.Error("Failed To Restore Claimed Fire", zap.String("taskId", t.ID), zap.Error(err))
+	}
 }
 
-// discardTaskNow removes a task from the scheduler and cancels any pending timers.
-func (s *SchedulerService) discardTaskNow(taskID string) {
-	s.timersMu.Lock()
-	scheduleKey := timerKey{kind: "schedule", taskID: taskID}
-	if cancelFunc, exists := s.timers[scheduleKey]; exists {
-		cancelFunc()
-		delete(s.timers, scheduleKey)
+// executeTask executes the task immediately, regardless of its schedule.
+// In queue mode the fire is enqueued for a worker instead; a replay carries
+// its request along, since it may differ from the task's own.
+func (s *SchedulerService) executeTask(ctx context.Context, t models.Task, trigger models.Trigger) error {
+	s.logger.Info("Executing Task Now", zap.String("taskId", t.ID), zap.String("trigger", string(trigger)))
+	// A claim on the task belongs to a scheduled run that may be in flight.
+	t.Claim = nil
+	if !s.queued {
+		if !s.newExecutor(t, trigger).Start() {
+			return errors.NewError(errors.Conflict, "scheduler is shutting down, the run was not started")
+		}
+		return nil
 	}
-	discardKey := timerKey{kind: "discard", taskID: taskID}
-	if cancelFunc, exists := s.timers[discardKey]; exists {
-		cancelFunc()
-		delete(s.timers, discardKey)
+
+	job := newJob(uuid.New().String(), t, trigger, int64(helpers.CurrentUTCUnix()))
+	if trigger == models.TriggerReplay {
+		job.Data = &t.TaskData
 	}
-	s.timersMu.Unlock()
-
-	s.tasksMu.Lock()
-	defer s.tasksMu.Unlock()
-	if entryID, exists := s.tasks[taskID]; exists {
-		s.cron.Remove(entryID)
-		delete(s.tasks, taskID)
-		s.logger.Info("Successfully Discarded Task", zap.String("taskId", taskID))
-		return
+	if err := s.queue.Enqueue(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
 	}
-	s.logger.Info("No Active Task Found To Discard", zap.String("taskId", taskID))
+	return nil
 }
 
-// discardTaskWithDelay removes the task after the given duration.
-// The context is pre-registered by the caller before this goroutine starts.
-func (s *SchedulerService) discardTaskWithDelay(ctx context.Context, duration time.Duration, taskID string) {
-	key := timerKey{kind: "discard", taskID: taskID}
-	defer func() {
-		s.timersMu.Lock()
-		delete(s.timers, key)
-		s.timersMu.Unlock()
-	}()
-
-	timer := time.NewTimer(duration)
-	defer timer.Stop()
-
-	select {
-	case <-timer.C:
-		s.discardTaskNow(taskID)
-	case <-ctx.Done():
-		s.logger.Info("Cancelled Pending Discard Timer", zap.String("taskId", taskID))
+func newJob(id string, t models.Task, trigger models.Trigger, fireAt int64) models.Job {
+	return models.Job{
+		ID:         id,
+		TaskID:     t.ID,
+		Namespace:  t.Namespace,
+		Trigger:    trigger,
+		FireAt:     fireAt,
+		EnqueuedAt: helpers.GetCurrentDateTime(),
 	}
 }
 
-// executeTaskNow executes the task immediately, regardless of its cron schedule.
-func (s *SchedulerService) executeTaskNow(t models.Task) {
-	s.logger.Info("Executing Task Now", zap.String("taskId", t.ID))
-	executor := executer.NewExecutorService(s.execCtx, s.logger, t, s.schedulerRepo, s.slack, s.client)
-	go executor.Run()
+// newExecutor builds an executor for the task bound to the shared execution context.
+func (s *SchedulerService) newExecutor(t models.Task, trigger models.Trigger) *executer.ExecutorService {
+	return executer.NewExecutorService(s.execCtx, t, trigger, s.execDeps)
 }
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	// Go Internal Packages
+	"context"
+	"testing"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+	executer "scheduler/services/executer"
+	helpers "scheduler/utils/helpers"
+
+	// External Packages
+	"go.uber.org/zap"
+)
+
+type claimCall struct {
+	due, next int64
+	claim     *models.FireClaim
+}
+
+// fakeRepo records claims; other methods are not expected to be called.
+type fakeRepo struct {
+	SchedulerRepo
+	claims []claimCall
+}
+
+func (r *fakeRepo) ClaimRun(_ context.Context, _ string, due, next int64, _ string, claim *models.FireClaim) (bool, error) {
+	r.claims = append(r.claims, claimCall{due: due, next: next, claim: claim})
+	return true, nil
+}
+
+type fakeQueue struct {
+	JobQueue
+	jobs []models.Job
+}
+
+func (q *fakeQueue) Enqueue(_ context.Context, job models.Job) error {
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+// newTestService returns a service whose registry no longer accepts runs,
+// as during shutdown.
+func newTestService(repo *fakeRepo, queue *fakeQueue) *SchedulerService {
+	runs := newRunRegistry()
+	runs.close()
+	return &SchedulerService{
+		logger:        zap.NewNop(),
+		schedulerRepo: repo,
+		execDeps:      &executer.Dependencies{Logger: zap.NewNop(), Runs: runs},
+		runs:          runs,
+		queue:         queue,
+		queued:        queue != nil,
+		instance:      "instance-1",
+		execCtx:       context.Background(),
+	}
+}
+
+func TestClaim(t *testing.T) {
+	const due, curUnix = 1000, 1005
+	task := models.Task{ID: "t1", StartUnix: 0, EndUnix: 10000, Recur: 3600, IsRecurEnabled: true, Enable: true, NextRunAt: due}
+	next := task.NextRunAfterFire(curUnix)
+
+	tests := []struct {
+		name       string
+		queued     bool
+		wantOK     bool
+		wantClaims []claimCall
+		wantJobs   int
+	}{
+		{
+			name:   "inline run refused gives the fire back",
+			wantOK: false,
+			wantClaims: []claimCall{
+				{due: due, next: next, claim: &models.FireClaim{FireAt: due, Instance: "instance-1", HeartbeatAt: curUnix}},
+				{due: next, next: due},
+			},
+		},
+		{
+			name:       "queued fire is enqueued without a claim",
+			queued:     true,
+			wantOK:     true,
+			wantClaims: []claimCall{{due: due, next: next}},
+			wantJobs:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{}
+			var queue *fakeQueue
+			if tt.queued {
+				queue = &fakeQueue{}
+			}
+			s := newTestService(repo, queue)
+
+			ok, err := s.claim(context.Background(), task, helpers.Unix(curUnix))
+			if err != nil || ok != tt.wantOK {
+				t.Fatalf("claim() = %v, %v; want %v, nil", ok, err, tt.wantOK)
+			}
+			if len(repo.claims) != len(tt.wantClaims) {
+				t.Fatalf("ClaimRun called %d times, want %d", len(repo.claims), len(tt.wantClaims))
+			}
+			for i, want := range tt.wantClaims {
+				got := repo.claims[i]
+				if got.due != want.due || got.next != want.next || (got.claim == nil) != (want.claim == nil) ||
+					(got.claim != nil && *got.claim != *want.claim) {
+					t.Errorf("ClaimRun call %d = %+v, want %+v", i, got, want)
+				}
+			}
+			if queue != nil && len(queue.jobs) != tt.wantJobs {
+				t.Errorf("enqueued %d jobs, want %d", len(queue.jobs), tt.wantJobs)
+			}
+		})
+	}
+}
+
+func TestExecuteTaskRefused(t *testing.T) {
+	s := newTestService(&fakeRepo{}, nil)
+	err := s.executeTask(context.Background(), models.Task{ID: "t1"}, models.TriggerManual)
+	var e *errors.Error
+	if !errors.As(err, &e) || e.Kind != errors.Conflict {
+		t.Errorf("executeTask() error = %v, want a Conflict error", err)
+	}
+}
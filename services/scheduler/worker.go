@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+	models "scheduler/models"
+	executer "scheduler/services/executer"
+	helpers "scheduler/utils/helpers"
+	httpclient "scheduler/utils/httpclient"
+	notifications "scheduler/utils/notifications"
+	secrets "scheduler/utils/secrets"
+
+	// External Packages
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+)
+
+// queueOpTimeout bounds queue writes made outside the lease loop, which use a
+// context detached from shutdown so that finished jobs are still completed.
+const queueOpTimeout = 10 * time.Second
+
+// JobQueue is the execution queue between the scheduler and its workers.
+type JobQueue interface {
+	Enqueue(ctx context.Context, job models.Job) error
+	Lease(ctx context.Context, workerID string, curUnix helpers.Unix, leaseUntil int64) (models.Job, error)
+	Heartbeat(ctx context.Context, jobID, workerID string, leaseUntil int64) (bool, error)
+	Complete(ctx context.Context, jobID, workerID string) error
+}
+
+// Worker leases queued fires and executes them. Several workers, in one or
+// many processes, can share a queue: a job is hidden while leased and is
+// delivered again when its lease runs out, e.g. because its worker died.
+type Worker struct {
+	logger        *zap.Logger
+	id            string
+	schedulerRepo SchedulerRepo
+	queue         JobQueue
+	execDeps      *executer.Dependencies
+	runs          *runRegistry
+	cfg           config.Worker
+	execCtx       context.Context
+	execCancel    context.CancelFunc
+}
+
+func NewWorker(logger *zap.Logger, schedulerRepo SchedulerRepo, queue JobQueue, slack notifications.Sender, client *httpclient.Client, secrets *secrets.Resolver, quota QuotaService, pool *executer.Pool, limiter *executer.RateLimiter, breaker *executer.Breaker, deadLetters executer.DeadLetterStore, completions executer.CompletionStore, callbackURL string, cfg config.Worker) *Worker {
+	hostname, _ := os.Hostname()
+	execCtx, execCancel := context.WithCancel(context.Background())
+	runs := newRunRegistry()
+	return &Worker{
+		logger:        logger,
+		id:            fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.New().String()[:8]),
+		schedulerRepo: schedulerRepo,
+		queue:         queue,
+		execDeps: &executer.Dependencies{
+			Logger:      logger,
+			Repo:        schedulerRepo,
+			Slack:       slack,
+			Client:      client,
+			Secrets:     secrets,
+			Quota:       quota,
+			Runs:        runs,
+			Pool:        pool,
+			Limiter:     limiter,
+			Breaker:     breaker,
+			DeadLetters: deadLetters,
+			Completions: completions,
+			CallbackURL: callbackURL,
+		},
+		runs:       runs,
+		cfg:        cfg,
+		execCtx:    execCtx,
+		execCancel: execCancel,
+	}
+}
+
+// Run executes queued jobs with cfg.Concurrency slots until ctx is done, then
+// stops leasing and drains the runs in flight for up to drainTimeout.
+func (w *Worker) Run(ctx context.Context, drainTimeout time.Duration) {
+	w.logger.Info("Starting Worker", zap.String("workerId", w.id), zap.Int("concurrency", w.cfg.Concurrency))
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.leaseLoop(ctx)
+		}()
+	}
+
+	<-ctx.Done()
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	w.runs.drain(drainCtx, w.logger)
+	wg.Wait()
+	w.execCancel()
+	w.logger.Info("Worker Stopped", zap.String("workerId", w.id))
+}
+
+// leaseLoop leases and processes one job at a time, waiting a poll interval
+// whenever the queue is empty.
+func (w *Worker) leaseLoop(ctx context.Context) {
+	for ctx.Err() == nil {
+		curUnix := helpers.CurrentUTCUnix()
+		job, err := w.queue.Lease(ctx, w.id, curUnix, w.leaseUntil())
+		if err == nil {
+			w.process(job)
+			continue
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) && ctx.Err() == nil {
+			w.logger.Error("Failed To Lease Job", zap.Error(err))
+		}
+
+		timer := time.NewTimer(w.cfg.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// process runs the task of a leased job against its current definition and
+// completes the job. A job is given up, failing its run, once it has been
+// delivered more than MaxDeliveries times.
+func (w *Worker) process(job models.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueOpTimeout)
+	defer cancel()
+	logger := w.logger.With(zap.String("jobId", job.ID), zap.String("taskId", job.TaskID))
+
+	t, err := w.schedulerRepo.GetOne(ctx, job.Namespace, job.TaskID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Info("Task Of Job No Longer Exists, Skipping")
+		w.complete(logger, job)
+		return
+	}
+	if err != nil {
+		// Leave the job leased; it is delivered again once the lease runs out.
+		logger.Error("Failed To Fetch Task Of Job", zap.Error(err))
+		return
+	}
+	if job.Trigger == models.TriggerSchedule && !t.Enable {
+		logger.Info("Task Disabled Since Job Was Enqueued, Skipping")
+		w.complete(logger, job)
+		return
+	}
+
+	if job.Data != nil {
+		t.TaskData = *job.Data
+	}
+	// Claims belong to runs executed in process; the job's lease covers this one.
+	t.Claim = nil
+
+	if job.Deliveries > w.cfg.MaxDeliveries {
+		logger.Error("Job Abandoned After Max Deliveries", zap.Int("deliveries", job.Deliveries))
+		msg := fmt.Sprintf("job abandoned after %d deliveries", job.Deliveries-1)
+		executer.NewExecutorService(ctx, t, job.Trigger, w.execDeps).Abandon(msg)
+		w.complete(logger, job)
+		return
+	}
+
+	stopHeartbeat := w.heartbeat(logger, job)
+	ran := executer.NewExecutorService(w.execCtx, t, job.Trigger, w.execDeps).Run()
+	stopHeartbeat()
+	if ran {
+		w.complete(logger, job)
+	}
+}
+
+// heartbeat extends the job's lease every heartbeat interval until the
+// returned function is called.
+func (w *Worker) heartbeat(logger *zap.Logger, job models.Job) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(w.cfg.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			held, err := w.queue.Heartbeat(ctx, job.ID, w.id, w.leaseUntil())
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("Failed To Extend Job Lease", zap.Error(err))
+				}
+				continue
+			}
+			if !held {
+				logger.Warn("Lost Lease On Job, It May Be Delivered Again")
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (w *Worker) complete(logger *zap.Logger, job models.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueOpTimeout)
+	defer cancel()
+	if err := w.queue.Complete(ctx, job.ID, w.id); err != nil {
+		logger.Error("Failed To Complete Job", zap.Error(err))
+	}
+}
+
+func (w *Worker) leaseUntil() int64 {
+	return int64(helpers.CurrentUTCUnix()) + int64(w.cfg.VisibilityTimeout/time.Second)
+}
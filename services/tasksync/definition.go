@@ -0,0 +1,123 @@
+package tasksync
+
+import (
+	// Go Internal Packages
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	// Local Packages
+	models "scheduler/models"
+
+	// External Packages
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is a task declared in a YAML file. Fields use the same names as
+// the create API body, plus a name that identifies the task across syncs.
+type Definition struct {
+	Name string `json:"name"`
+	models.CreateRequest
+	File string `json:"-"`
+}
+
+// LoadDir reads every .yml/.yaml file under dir. A file may hold several
+// definitions separated by "---".
+func LoadDir(dir string) ([]Definition, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if !d.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	sort.Strings(files)
+
+	var defs []Definition
+	seen := make(map[string]string)
+	for _, path := range files {
+		fileDefs, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, def := range fileDefs {
+			if prev, ok := seen[def.Name]; ok {
+				return nil, fmt.Errorf("%s: task %q is already defined in %s", path, def.Name, prev)
+			}
+			seen[def.Name] = path
+			defs = append(defs, def)
+		}
+	}
+	return defs, nil
+}
+
+func loadFile(path string) ([]Definition, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var defs []Definition
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for i := 0; ; i++ {
+		var doc map[string]any
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", path, i, err)
+		}
+		if doc == nil {
+			continue
+		}
+
+		// Round-trip through JSON so the definition shares the API's field names.
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", path, i, err)
+		}
+		var def Definition
+		jd := json.NewDecoder(bytes.NewReader(raw))
+		jd.DisallowUnknownFields()
+		if err := jd.Decode(&def); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", path, i, err)
+		}
+		def.File = path
+		if err := def.validate(); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", path, i, err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// validate checks the fields that sync relies on. The task itself is
+// validated by the server when it is created or updated.
+func (d *Definition) validate() error {
+	switch {
+	case d.Name == "":
+		return fmt.Errorf("name cannot be empty")
+	case !models.ValidLabelValue(d.Name):
+		return fmt.Errorf("invalid name %q, expected alphanumerics, '-', '_' or '.' (max 63)", d.Name)
+	case d.ExternalID != "":
+		return fmt.Errorf("externalId is managed by sync and cannot be set")
+	}
+	for key := range d.Labels {
+		if strings.HasPrefix(key, models.ReservedLabelPrefix) {
+			return fmt.Errorf("label %q uses the reserved %q prefix", key, models.ReservedLabelPrefix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package tasksync
+
+import (
+	// Go Internal Packages
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+
+	// Local Packages
+	models "scheduler/models"
+	helpers "scheduler/utils/helpers"
+)
+
+// Labels set on every task created by sync. Only tasks carrying LabelManagedBy
+// are ever updated, disabled or pruned, so manually created tasks are left alone.
+const (
+	LabelManagedBy = models.LabelManagedBy
+	LabelName      = models.LabelSyncName
+	LabelSpecHash  = models.LabelSpecHash
+	ManagedByValue = models.ManagedBySync
+)
+
+// ManagedSelector selects the tasks owned by sync.
+const ManagedSelector = LabelManagedBy + "=" + ManagedByValue
+
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDisable Action = "disable"
+	ActionDelete  Action = "delete"
+)
+
+var actionSymbols = map[Action]string{
+	ActionCreate:  "+",
+	ActionUpdate:  "~",
+	ActionDisable: "!",
+	ActionDelete:  "-",
+}
+
+type Change struct {
+	Action  Action
+	Name    string
+	TaskID  string
+	File    string
+	Request models.CreateRequest
+}
+
+type Plan struct {
+	Changes   []Change
+	Unchanged int
+}
+
+// TaskClient is the subset of the API used to apply a plan.
+type TaskClient interface {
+	CreateTask(ctx context.Context, req models.CreateRequest) (string, error)
+	UpdateTask(ctx context.Context, taskID string, req models.CreateRequest) error
+	DisableTask(ctx context.Context, taskID string) error
+	DeleteTask(ctx context.Context, taskID string) error
+}
+
+// BuildPlan diffs the definitions against the stored tasks owned by sync.
+// Definitions without a stored task are created and changed ones updated; a
+// task disabled since, e.g. because its definition was removed, counts as changed.
+// Stored tasks that are no longer defined are deleted when prune is set and
+// disabled otherwise.
+func BuildPlan(defs []Definition, existing []models.Task, prune bool) Plan {
+	stored := make(map[string]models.Task, len(existing))
+	var orphans []models.Task
+	for _, t := range existing {
+		if t.Labels[LabelManagedBy] != ManagedByValue {
+			continue
+		}
+		name := t.Labels[LabelName]
+		if _, dup := stored[name]; dup || name == "" {
+			orphans = append(orphans, t)
+			continue
+		}
+		stored[name] = t
+	}
+
+	var plan Plan
+	for _, def := range defs {
+		req := def.request()
+		t, ok := stored[def.Name]
+		delete(stored, def.Name)
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Name: def.Name, File: def.File, Request: req})
+		case t.Labels[LabelSpecHash] != req.Labels[LabelSpecHash] || t.Enable != req.Enable:
+			plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Name: def.Name, TaskID: t.ID, File: def.File, Request: req})
+		default:
+			plan.Unchanged++
+		}
+	}
+
+	for _, t := range stored {
+		orphans = append(orphans, t)
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].ID < orphans[j].ID })
+	for _, t := range orphans {
+		switch {
+		case prune:
+			plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Name: t.Labels[LabelName], TaskID: t.ID})
+		case t.Enable:
+			plan.Changes = append(plan.Changes, Change{Action: ActionDisable, Name: t.Labels[LabelName], TaskID: t.ID})
+		default:
+			plan.Unchanged++
+		}
+	}
+	return plan
+}
+
+// request builds the create request for the definition with the ownership
+// labels and a hash of the definition used to detect changes.
+func (d *Definition) request() models.CreateRequest {
+	req := d.CreateRequest
+	b, _ := json.Marshal(req)
+	req.Labels = maps.Clone(d.Labels)
+	if req.Labels == nil {
+		req.Labels = make(map[string]string)
+	}
+	req.Labels[LabelManagedBy] = ManagedByValue
+	req.Labels[LabelName] = d.Name
+	req.Labels[LabelSpecHash] = helpers.SHA256(string(b))[:16]
+	return req
+}
+
+func (p Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+func (p Plan) Print(w io.Writer) {
+	for _, c := range p.Changes {
+		line := fmt.Sprintf("%s %-7s %s", actionSymbols[c.Action], c.Action, c.Name)
+		if c.TaskID != "" {
+			line += " (" + c.TaskID + ")"
+		}
+		if c.File != "" {
+			line += " from " + c.File
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_, _ = fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to disable, %d to delete, %d unchanged.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDisable), p.Count(ActionDelete), p.Unchanged)
+}
+
+// Apply executes every change in order, continuing past failures, and returns
+// an error if any change failed.
+func Apply(ctx context.Context, client TaskClient, plan Plan, w io.Writer) error {
+	failed := 0
+	for _, c := range plan.Changes {
+		var err error
+		switch c.Action {
+		case ActionCreate:
+			c.TaskID, err = client.CreateTask(ctx, c.Request)
+		case ActionUpdate:
+			err = client.UpdateTask(ctx, c.TaskID, c.Request)
+		case ActionDisable:
+			err = client.DisableTask(ctx, c.TaskID)
+		case ActionDelete:
+			err = client.DeleteTask(ctx, c.TaskID)
+		}
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(w, "failed to %s %s: %v\n", c.Action, c.Name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%sd %s (%s)\n", c.Action, c.Name, c.TaskID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d changes failed", failed, len(plan.Changes))
+	}
+	return nil
+}
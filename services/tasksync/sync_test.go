@@ -0,0 +1,54 @@
+package tasksync
+
+import (
+	// Go Internal Packages
+	"testing"
+
+	// Local Packages
+	models "scheduler/models"
+)
+
+func TestBuildPlan(t *testing.T) {
+	def := Definition{Name: "nightly", CreateRequest: models.CreateRequest{Enable: true, Recur: 86400, IsRecurEnabled: true}}
+	synced := func(id string, d Definition, enable bool) models.Task {
+		req := d.request()
+		return models.Task{ID: id, Labels: req.Labels, Enable: enable}
+	}
+	changed := def
+	changed.Recur = 3600
+
+	tests := []struct {
+		name      string
+		defs      []Definition
+		existing  []models.Task
+		prune     bool
+		want      []Action
+		unchanged int
+	}{
+		{"new definition", []Definition{def}, nil, false, []Action{ActionCreate}, 0},
+		{"same definition", []Definition{def}, []models.Task{synced("t1", def, true)}, false, nil, 1},
+		{"changed definition", []Definition{changed}, []models.Task{synced("t1", def, true)}, false, []Action{ActionUpdate}, 0},
+		{"disabled task defined again", []Definition{def}, []models.Task{synced("t1", def, false)}, false, []Action{ActionUpdate}, 0},
+		{"removed definition", nil, []models.Task{synced("t1", def, true)}, false, []Action{ActionDisable}, 0},
+		{"removed definition already disabled", nil, []models.Task{synced("t1", def, false)}, false, nil, 1},
+		{"removed definition pruned", nil, []models.Task{synced("t1", def, false)}, true, []Action{ActionDelete}, 0},
+		{"task not owned by sync", nil, []models.Task{{ID: "t1", Enable: true}}, true, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := BuildPlan(tt.defs, tt.existing, tt.prune)
+			var got []Action
+			for _, c := range plan.Changes {
+				got = append(got, c.Action)
+			}
+			if len(got) != len(tt.want) || plan.Unchanged != tt.unchanged {
+				t.Fatalf("BuildPlan() = %v with %d unchanged, want %v with %d", got, plan.Unchanged, tt.want, tt.unchanged)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("BuildPlan() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package apiclient
+
+import (
+	// Go Internal Packages
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	// Local Packages
+	errors "scheduler/errors"
+	models "scheduler/models"
+)
+
+const listPageSize = 1000
+
+// Client is a small client for the scheduler REST API used by the CLI
+// subcommands. All task calls are scoped to a single namespace.
+type Client struct {
+	baseURL    string
+	namespace  string
+	apiKey     string
+	token      string
+	httpClient *http.Client
+}
+
+// New returns a client for the API at baseURL (including the version prefix,
+// e.g. http://localhost:4202/scheduler/v1). Either apiKey or a bearer token
+// may be set; the token wins when both are given.
+func New(baseURL, namespace, apiKey, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		namespace:  models.NamespaceOrDefault(namespace),
+		apiKey:     apiKey,
+		token:      token,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Error is a non-2xx response from the API.
+type Error struct {
+	Status           int
+	Message          string                  `json:"message"`
+	ValidationErrors errors.ValidationErrors `json:"validation_errors"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("api error %d: %s", e.Status, e.Message)
+	for _, fe := range e.ValidationErrors {
+		msg += fmt.Sprintf("; %s: %s", fe.Field, fe.Error)
+	}
+	return msg
+}
+
+// ListTasks returns every task in the namespace matching selector, following pagination.
+func (c *Client) ListTasks(ctx context.Context, selector string) ([]models.Task, error) {
+	var tasks []models.Task
+	for offset := 0; ; offset += listPageSize {
+		q := url.Values{}
+		q.Set("selector", selector)
+		q.Set("limit", strconv.Itoa(listPageSize))
+		q.Set("offset", strconv.Itoa(offset))
+
+		var page models.TaskList
+		if err := c.do(ctx, http.MethodGet, c.taskPath("")+"?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page.Tasks...)
+		if len(page.Tasks) < listPageSize {
+			return tasks, nil
+		}
+	}
+}
+
+// CreateTask creates a task through the sync route, which accepts the
+// ownership labels of sync, and returns its ID.
+func (c *Client) CreateTask(ctx context.Context, req models.CreateRequest) (string, error) {
+	var res struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, c.taskPath("")+"/sync", req, &res); err != nil {
+		return "", err
+	}
+	return res.TaskID, nil
+}
+
+// UpdateTask replaces a task's definition through the sync route.
+func (c *Client) UpdateTask(ctx context.Context, taskID string, req models.CreateRequest) error {
+	return c.do(ctx, http.MethodPut, c.taskPath(taskID)+"/sync", req, nil)
+}
+
+func (c *Client) DisableTask(ctx context.Context, taskID string) error {
+	return c.do(ctx, http.MethodPatch, c.taskPath(taskID)+"/disable", nil, nil)
+}
+
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	return c.do(ctx, http.MethodDelete, c.taskPath(taskID), nil, nil)
+}
+
+// ExportTasks writes the namespace's tasks matching selector to w in the given format.
+func (c *Client) ExportTasks(ctx context.Context, format models.TransferFormat, selector string, w io.Writer) error {
+	q := url.Values{}
+	q.Set("format", string(format))
+	q.Set("selector", selector)
+	resp, err := c.send(ctx, http.MethodGet, c.taskPath("export")+"?"+q.Encode(), "", nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if _, err = io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+	return nil
+}
+
+// ImportTasks uploads exported tasks read from r.
+func (c *Client) ImportTasks(ctx context.Context, format models.TransferFormat, r io.Reader, opts models.ImportOptions) (*models.BatchResult, error) {
+	q := url.Values{}
+	q.Set("format", string(format))
+	q.Set("mode", string(opts.Mode))
+	q.Set("preserve_ids", strconv.FormatBool(opts.PreserveIDs))
+	q.Set("enable", strconv.FormatBool(opts.Enable))
+	for from, to := range opts.HostRewrites {
+		q.Add("rewrite_host", from+"="+to)
+	}
+
+	resp, err := c.send(ctx, http.MethodPost, c.taskPath("import")+"?"+q.Encode(), format.ContentType(), r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var res models.BatchResult
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &res, nil
+}
+
+func (c *Client) taskPath(taskID string) string {
+	p := c.baseURL + "/namespaces/" + url.PathEscape(c.namespace) + "/task"
+	if taskID != "" {
+		p += "/" + url.PathEscape(taskID)
+	}
+	return p
+}
+
+// do sends body as JSON and decodes the JSON response into out, if set.
+func (c *Client) do(ctx context.Context, method, rawURL string, body, out any) error {
+	var reader io.Reader
+	contentType := ""
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(b)
+		contentType = "application/json"
+	}
+
+	resp, err := c.send(ctx, method, rawURL, contentType, reader)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// send performs an authenticated request, turning non-2xx responses into *Error.
+// The caller must close the body of the returned response.
+func (c *Client) send(ctx context.Context, method, rawURL, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.apiKey != "":
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer func() { _ = resp.Body.Close() }()
+		apiErr := &Error{Status: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
@@ -3,6 +3,7 @@ package helpers
 import (
 	// Go Internal Packages
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -36,3 +37,9 @@ func UnmarshalInterface(res any, mp map[string]any, key string) error {
 	}
 	return nil
 }
+
+// SHA256 returns the hex encoded SHA-256 hash of the given string
+func SHA256(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -14,6 +14,9 @@ func GetExpiryTime() string {
 	return time.Now().UTC().AddDate(10, 0, 0).Format("2006-01-02T15:04:05.999Z")
 }
 
+// ISTZone is the zone of task schedule dates and times.
+const ISTZone = "Asia/Kolkata"
+
 type Unix int64
 
 func CurrentUTCUnix() Unix {
@@ -21,7 +24,7 @@ func CurrentUTCUnix() Unix {
 }
 
 func ToUnixFromISTDateTime(scheduleTime, scheduleDate string) (int64, error) {
-	loc, err := time.LoadLocation("Asia/Kolkata")
+	loc, err := time.LoadLocation(ISTZone)
 	if err != nil {
 		return 0, fmt.Errorf("failed to load IST location: %w", err)
 	}
@@ -44,3 +47,8 @@ func ToUnixFromUTCTime(utcTime string) (int64, error) {
 func (a Unix) DurationFrom(b Unix) time.Duration {
 	return time.Duration(a-b) * time.Second
 }
+
+// Time returns the UTC time of the unix timestamp.
+func (a Unix) Time() time.Time {
+	return time.Unix(int64(a), 0).UTC()
+}
@@ -0,0 +1,183 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BodyType selects how a request body is encoded.
+type BodyType string
+
+const (
+	BodyJSON      BodyType = "json"      // Body is a map, sent as a JSON object
+	BodyForm      BodyType = "form"      // Body is a map of scalars or arrays of scalars
+	BodyMultipart BodyType = "multipart" // Body as for form, plus Files
+	BodyRaw       BodyType = "raw"       // Body is a string, sent as is
+	BodyBinary    BodyType = "binary"    // Body is a []byte, sent as is
+)
+
+func (b BodyType) Validate() error {
+	switch b {
+	case BodyJSON, BodyForm, BodyMultipart, BodyRaw, BodyBinary:
+		return nil
+	default:
+		return fmt.Errorf("invalid body type: %s", string(b))
+	}
+}
+
+// File is a file part of a multipart body.
+type File struct {
+	Field       string
+	Filename    string
+	ContentType string // default application/octet-stream
+	Content     []byte
+}
+
+// encodeBody encodes the body of r and returns it with its default
+// Content-Type. A request without a body yields a nil reader.
+func encodeBody(r Request) (io.Reader, string, error) {
+	bodyType := r.BodyType
+	if bodyType == "" {
+		bodyType = BodyJSON
+	}
+	if r.Body == nil && (bodyType != BodyMultipart || len(r.Files) == 0) {
+		return nil, "", nil
+	}
+
+	switch bodyType {
+	case BodyJSON:
+		b, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		return bytes.NewReader(b), "application/json", nil
+	case BodyForm:
+		fields, _ := r.Body.(map[string]any)
+		values := url.Values{}
+		for key, value := range fields {
+			items, err := FormValues(value)
+			if err != nil {
+				return nil, "", fmt.Errorf("form field %s: %w", key, err)
+			}
+			values[key] = items
+		}
+		return strings.NewReader(values.Encode()), "application/x-www-form-urlencoded", nil
+	case BodyMultipart:
+		return encodeMultipart(r)
+	case BodyRaw:
+		s, ok := r.Body.(string)
+		if !ok {
+			return nil, "", fmt.Errorf("raw body must be a string")
+		}
+		return strings.NewReader(s), "text/plain; charset=utf-8", nil
+	case BodyBinary:
+		b, ok := r.Body.([]byte)
+		if !ok {
+			return nil, "", fmt.Errorf("binary body must be bytes")
+		}
+		return bytes.NewReader(b), "application/octet-stream", nil
+	}
+	return nil, "", bodyType.Validate()
+}
+
+// encodeMultipart writes the fields, in key order, followed by the files.
+func encodeMultipart(r Request) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fields, _ := r.Body.(map[string]any)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		items, err := FormValues(fields[key])
+		if err != nil {
+			return nil, "", fmt.Errorf("form field %s: %w", key, err)
+		}
+		for _, item := range items {
+			if err := w.WriteField(key, item); err != nil {
+				return nil, "", fmt.Errorf("failed to write form field: %w", err)
+			}
+		}
+	}
+
+	for _, f := range r.Files {
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(f.Field), escapeQuotes(f.Filename)))
+		h.Set("Content-Type", contentType)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create file part: %w", err)
+		}
+		if _, err := part.Write(f.Content); err != nil {
+			return nil, "", fmt.Errorf("failed to write file part: %w", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart body: %w", err)
+	}
+	return &buf, w.FormDataContentType(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// FormValues returns the text of a form value: a scalar gives one value and
+// an array of scalars one value per element. Objects are not allowed.
+func FormValues(value any) ([]string, error) {
+	if s, ok := FormatScalar(value); ok {
+		return []string{s}, nil
+	}
+	// Arrays decoded from Mongo are named slice types (bson.A), so match on kind.
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Interface {
+		return nil, fmt.Errorf("must be a string, number, boolean or an array of them")
+	}
+	out := make([]string, 0, rv.Len())
+	for i := range rv.Len() {
+		s, ok := FormatScalar(rv.Index(i).Interface())
+		if !ok {
+			return nil, fmt.Errorf("array elements must be strings, numbers or booleans")
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// FormatScalar returns the text of a string, number or boolean.
+func FormatScalar(value any) (string, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncodeBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      Request
+		wantType string
+		wantBody string // for multipart, a substring of the body
+		wantErr  bool
+	}{
+		{"json by default", Request{Body: map[string]any{"a": 1}}, "application/json", `{"a":1}`, false},
+		{"form", Request{BodyType: BodyForm, Body: map[string]any{"b": "x y", "a": []any{1, true}}}, "application/x-www-form-urlencoded", "a=1&a=true&b=x+y", false},
+		{"form object rejected", Request{BodyType: BodyForm, Body: map[string]any{"a": map[string]any{}}}, "", "", true},
+		{"raw", Request{BodyType: BodyRaw, Body: "<ping/>"}, "text/plain; charset=utf-8", "<ping/>", false},
+		{"raw not a string", Request{BodyType: BodyRaw, Body: []byte("x")}, "", "", true},
+		{"binary", Request{BodyType: BodyBinary, Body: []byte{0, 1}}, "application/octet-stream", "\x00\x01", false},
+		{"multipart file", Request{BodyType: BodyMultipart, Files: []File{{Field: "f", Filename: `a"b.csv`, Content: []byte("id")}}},
+			"multipart/form-data", `name="f"; filename="a\"b.csv"` + "\r\nContent-Type: application/octet-stream\r\n\r\nid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType, err := encodeBody(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			b, _ := io.ReadAll(body)
+			if !strings.HasPrefix(contentType, tt.wantType) || !strings.Contains(string(b), tt.wantBody) {
+				t.Errorf("encodeBody() = %q, %q; want %q, %q", b, contentType, tt.wantBody, tt.wantType)
+			}
+		})
+	}
+}
@@ -2,13 +2,11 @@ package httpclient
 
 import (
 	// Go Internal Packages
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
-	"strconv"
 	"time"
 )
 
@@ -38,19 +36,30 @@ func (m Method) String() string {
 }
 
 type Request struct {
-	URL         string
-	Method      Method
-	Headers     map[string]string
-	QueryParams map[string]any
-	Body        any
+	URL           string
+	Method        Method
+	Headers       map[string]string
+	QueryParams   map[string]any
+	QueryEncoding QueryEncoding // of nested query param values; empty allows none
+	BodyType      BodyType      // default json
+	Body          any           // see BodyType; nil sends no body
+	Files         []File        // multipart only
 }
 
 type Client struct {
 	httpClient *http.Client
+	policy     *Policy
 }
 
-func New() *Client {
+// New builds a client whose every connection and redirect is checked against policy.
+func New(policy *Policy) *Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   policy.control,
+	}
 	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 20,
 		MaxConnsPerHost:     50,
@@ -60,11 +69,31 @@ func New() *Client {
 		httpClient: &http.Client{
 			Timeout:   3 * time.Minute,
 			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return fmt.Errorf("stopped after 10 redirects")
+				}
+				// Task headers may carry credentials meant for the task's
+				// host only, so none are sent on to another one.
+				if req.URL.Host != via[0].URL.Host {
+					contentType := req.Header.Get("Content-Type")
+					req.Header = make(http.Header)
+					if contentType != "" {
+						req.Header.Set("Content-Type", contentType)
+					}
+				}
+				return policy.CheckURL(req.URL.String())
+			},
 		},
+		policy: policy,
 	}
 }
 
 func (c *Client) Do(ctx context.Context, r Request) (*http.Response, error) {
+	if err := c.policy.CheckURL(r.URL); err != nil {
+		return nil, err
+	}
+
 	reqURL, err := url.Parse(r.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
@@ -72,38 +101,31 @@ func (c *Client) Do(ctx context.Context, r Request) (*http.Response, error) {
 
 	if len(r.QueryParams) > 0 {
 		q := reqURL.Query()
-		for key, value := range r.QueryParams {
-			switch v := value.(type) {
-			case string:
-				q.Set(key, v)
-			case int, int64, float64:
-				q.Set(key, fmt.Sprintf("%v", v))
-			case bool:
-				q.Set(key, strconv.FormatBool(v))
-			default:
-				return nil, fmt.Errorf("unsupported query param type for key %s", key)
-			}
+		if err := encodeQuery(q, r.QueryParams, r.QueryEncoding); err != nil {
+			return nil, err
 		}
 		reqURL.RawQuery = q.Encode()
 	}
 
-	var bodyBytes []byte
-	if r.Body != nil {
-		bodyBytes, err = json.Marshal(r.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
+	body, contentType, err := encodeBody(r)
+	if err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, r.Method.String(), reqURL.String(), bytes.NewBuffer(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, r.Method.String(), reqURL.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if r.Body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	// A Content-Type header of the task overrides the default, except for
+	// multipart bodies, whose boundary is part of it.
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 	for key, value := range r.Headers {
+		if r.BodyType == BodyMultipart && http.CanonicalHeaderKey(key) == "Content-Type" {
+			continue
+		}
 		req.Header.Set(key, value)
 	}
 
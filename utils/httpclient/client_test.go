@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectHeaders(t *testing.T) {
+	var got http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer target.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/same" {
+			http.Redirect(w, r, "/done", http.StatusFound)
+			return
+		}
+		if r.URL.Path == "/done" {
+			got = r.Header
+			return
+		}
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantKept bool
+	}{
+		{"same host keeps task headers", "/same", true},
+		{"other host drops task headers", "/away", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			resp, err := New(nil).Do(context.Background(), Request{
+				URL:     origin.URL + tt.path,
+				Method:  GET,
+				Headers: map[string]string{"X-Api-Token": "secret"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if kept := got.Get("X-Api-Token") != ""; kept != tt.wantKept {
+				t.Errorf("task header forwarded = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+)
+
+// ErrBlockedDestination is returned when a URL or resolved address is not
+// permitted by the outbound policy.
+var ErrBlockedDestination = errors.New("destination blocked by outbound policy")
+
+// privateRanges are blocked unless explicitly allowed when block_private is set.
+var privateRanges = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("10.0.0.0/8"),     // RFC 1918
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),    // loopback
+	netip.MustParsePrefix("169.254.0.0/16"), // link-local, cloud metadata
+	netip.MustParsePrefix("172.16.0.0/12"),  // RFC 1918
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("192.168.0.0/16"), // RFC 1918
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("224.0.0.0/4"),    // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, broadcast
+	netip.MustParsePrefix("::/128"),         // unspecified
+	netip.MustParsePrefix("::1/128"),        // loopback
+	netip.MustParsePrefix("fc00::/7"),       // unique local
+	netip.MustParsePrefix("fe80::/10"),      // link-local
+	netip.MustParsePrefix("ff00::/8"),       // multicast
+}
+
+// Policy decides which outbound destinations tasks may call. It is checked
+// when a task is validated and again at dial time against the resolved
+// address, so a hostname cannot be re-pointed at a blocked address later.
+// A nil policy permits every destination.
+type Policy struct {
+	schemes      map[string]bool
+	allowHosts   []string
+	denyHosts    []string
+	allowCIDRs   []netip.Prefix
+	denyCIDRs    []netip.Prefix
+	blockPrivate bool
+}
+
+func NewPolicy(cfg config.Outbound) (*Policy, error) {
+	p := &Policy{
+		schemes:      make(map[string]bool, len(cfg.AllowedSchemes)),
+		allowHosts:   normalizeHosts(cfg.AllowHosts),
+		denyHosts:    normalizeHosts(cfg.DenyHosts),
+		blockPrivate: cfg.BlockPrivate,
+	}
+	for _, scheme := range cfg.AllowedSchemes {
+		p.schemes[strings.ToLower(scheme)] = true
+	}
+
+	var err error
+	if p.allowCIDRs, err = parsePrefixes(cfg.AllowCIDRs); err != nil {
+		return nil, fmt.Errorf("invalid allow_cidrs: %w", err)
+	}
+	if p.denyCIDRs, err = parsePrefixes(cfg.DenyCIDRs); err != nil {
+		return nil, fmt.Errorf("invalid deny_cidrs: %w", err)
+	}
+	return p, nil
+}
+
+// CheckURL validates the scheme and host of rawURL. Literal IP hosts are
+// checked against the address rules; hostnames are checked again at dial time.
+func (p *Policy) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if p == nil {
+		if u.Hostname() == "" {
+			return fmt.Errorf("invalid url: missing host")
+		}
+		return nil
+	}
+	if !p.schemes[strings.ToLower(u.Scheme)] {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrBlockedDestination, u.Scheme)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return fmt.Errorf("invalid url: missing host")
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if len(p.allowHosts) > 0 && !containsAddr(p.allowCIDRs, ip.Unmap()) {
+			return fmt.Errorf("%w: address %s is not in the allow list", ErrBlockedDestination, ip)
+		}
+		return p.CheckIP(ip)
+	}
+
+	if matchHost(p.denyHosts, host) {
+		return fmt.Errorf("%w: host %q is denied", ErrBlockedDestination, host)
+	}
+	if len(p.allowHosts) > 0 && !matchHost(p.allowHosts, host) {
+		return fmt.Errorf("%w: host %q is not in the allow list", ErrBlockedDestination, host)
+	}
+	if p.blockPrivate && (host == "localhost" || strings.HasSuffix(host, ".localhost")) {
+		return fmt.Errorf("%w: host %q is a loopback name", ErrBlockedDestination, host)
+	}
+	return nil
+}
+
+// CheckIP validates a resolved or literal address. Deny CIDRs always win;
+// allow CIDRs exempt an address from the private range block and, when the
+// host allow list is set, are the only way to permit a literal IP.
+func (p *Policy) CheckIP(ip netip.Addr) error {
+	if p == nil {
+		return nil
+	}
+	ip = ip.Unmap()
+	if containsAddr(p.denyCIDRs, ip) {
+		return fmt.Errorf("%w: address %s is denied", ErrBlockedDestination, ip)
+	}
+	if containsAddr(p.allowCIDRs, ip) {
+		return nil
+	}
+	if p.blockPrivate && containsAddr(privateRanges, ip) {
+		return fmt.Errorf("%w: address %s is in a private range", ErrBlockedDestination, ip)
+	}
+	return nil
+}
+
+// control is used as net.Dialer.Control to check every address actually
+// dialled, after DNS resolution.
+func (p *Policy) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedDestination, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedDestination, err)
+	}
+	return p.CheckIP(ip)
+}
+
+func normalizeHosts(hosts []string) []string {
+	out := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// matchHost reports whether host matches any of patterns.
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if MatchHost(pattern, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchHost matches host against an exact name or a "*.example.com" wildcard,
+// which matches any subdomain of example.com but not example.com itself.
+func MatchHost(pattern, host string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(host, suffix)
+	}
+	return host == pattern
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(cidrs))
+	for _, c := range cidrs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(c))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, prefix.Masked())
+	}
+	return out, nil
+}
+
+func containsAddr(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
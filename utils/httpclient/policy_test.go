@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"errors"
+	"net/netip"
+	"testing"
+
+	// Local Packages
+	config "scheduler/config"
+)
+
+func TestPolicyCheckURL(t *testing.T) {
+	policy, err := NewPolicy(config.Outbound{
+		AllowedSchemes: []string{"https"},
+		DenyHosts:      []string{"*.internal.example.com"},
+		DenyCIDRs:      []string{"203.0.113.0/24"},
+		BlockPrivate:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		policy      *Policy
+		url         string
+		wantErr     bool
+		wantBlocked bool
+	}{
+		{"allowed", policy, "https://api.example.com/run", false, false},
+		{"scheme not allowed", policy, "http://api.example.com/run", true, true},
+		{"denied host", policy, "https://db.internal.example.com/run", true, true},
+		{"denied address", policy, "https://203.0.113.7/run", true, true},
+		{"private address", policy, "https://10.0.0.1/run", true, true},
+		{"loopback name", policy, "https://localhost/run", true, true},
+		{"missing host", policy, "https:///run", true, false},
+		{"nil policy allows any host", nil, "http://10.0.0.1/run", false, false},
+		{"nil policy still needs a host", nil, "/run", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.CheckURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got := errors.Is(err, ErrBlockedDestination); got != tt.wantBlocked {
+				t.Errorf("CheckURL(%q) blocked = %v, want %v", tt.url, got, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestNilPolicyCheckIP(t *testing.T) {
+	var p *Policy
+	if err := p.CheckIP(netip.MustParseAddr("127.0.0.1")); err != nil {
+		t.Errorf("CheckIP() on a nil policy = %v, want nil", err)
+	}
+}
@@ -0,0 +1,151 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// QueryEncoding selects how nested query param values, objects and arrays
+// that hold objects or arrays, are encoded. Scalars and arrays of scalars
+// need none: arrays are sent as repeated keys.
+type QueryEncoding string
+
+const (
+	QueryBrackets QueryEncoding = "brackets" // filter[status]=open&items[0][id]=1
+	QueryDots     QueryEncoding = "dots"     // filter.status=open&items.0.id=1
+	QueryJSON     QueryEncoding = "json"     // filter={"status":"open"}
+)
+
+func (e QueryEncoding) Validate() error {
+	switch e {
+	case QueryBrackets, QueryDots, QueryJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid query encoding: %s", string(e))
+	}
+}
+
+// ValidateQueryParam reports whether value can be sent as query param key
+// with the given encoding, which may be empty.
+func ValidateQueryParam(key string, value any, enc QueryEncoding) error {
+	return addQueryParam(url.Values{}, key, value, enc)
+}
+
+// encodeQuery adds params to q. A param replaces any value of the same key
+// already in the url.
+func encodeQuery(q url.Values, params map[string]any, enc QueryEncoding) error {
+	for key, value := range params {
+		q.Del(key)
+		if err := addQueryParam(q, key, value, enc); err != nil {
+			return fmt.Errorf("query param %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func addQueryParam(q url.Values, key string, value any, enc QueryEncoding) error {
+	if value == nil {
+		return fmt.Errorf("must not be null")
+	}
+	// An empty array would drop the param silently.
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice && rv.Len() == 0 {
+		return fmt.Errorf("must not be an empty array")
+	}
+	if items, err := FormValues(value); err == nil {
+		for _, item := range items {
+			q.Add(key, item)
+		}
+		return nil
+	}
+
+	switch enc {
+	case "":
+		return fmt.Errorf("nested values need a query encoding (brackets, dots or json)")
+	case QueryJSON:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal value: %w", err)
+		}
+		q.Add(key, string(b))
+		return nil
+	case QueryBrackets, QueryDots:
+		plain, err := plainJSON(value)
+		if err != nil {
+			return err
+		}
+		return flattenQuery(q, key, plain, enc)
+	}
+	return enc.Validate()
+}
+
+// flattenQuery adds the leaves of value under keys built from their path.
+// Object keys are added in order so that the query is stable.
+func flattenQuery(q url.Values, key string, value any, enc QueryEncoding) error {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("must not contain null at %s", key)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if err := flattenQuery(q, childKey(key, k, enc), v[k], enc); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if len(v) == 0 {
+			return fmt.Errorf("must not contain an empty array at %s", key)
+		}
+		if items, err := FormValues(v); err == nil {
+			for _, item := range items {
+				q.Add(key, item)
+			}
+			return nil
+		}
+		for i, item := range v {
+			if err := flattenQuery(q, childKey(key, strconv.Itoa(i), enc), item, enc); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	s, ok := FormatScalar(value)
+	if !ok {
+		return fmt.Errorf("unsupported value at %s", key)
+	}
+	q.Add(key, s)
+	return nil
+}
+
+func childKey(key, child string, enc QueryEncoding) string {
+	if enc == QueryDots {
+		return key + "." + child
+	}
+	return key + "[" + child + "]"
+}
+
+// plainJSON converts value to plain maps, slices and json.Numbers. Arrays
+// decoded from Mongo are bson.A rather than []any.
+func plainJSON(value any) (any, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode value: %w", err)
+	}
+	return out, nil
+}
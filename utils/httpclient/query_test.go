@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	// Go Internal Packages
+	"net/url"
+	"testing"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		params  map[string]any
+		enc     QueryEncoding
+		want    string
+		wantErr bool
+	}{
+		{"scalars", "", map[string]any{"q": "a b", "n": 2.5, "ok": true}, "", "n=2.5&ok=true&q=a+b", false},
+		{"array as repeated keys", "", map[string]any{"id": []any{"1", float64(2)}}, "", "id=1&id=2", false},
+		{"mongo array", "", map[string]any{"id": bson.A{"1", "2"}}, "", "id=1&id=2", false},
+		{"replaces url value", "page=1&keep=x", map[string]any{"page": "2"}, "", "keep=x&page=2", false},
+		{"brackets", "", map[string]any{"filter": map[string]any{"status": "open", "tags": []any{"a", "b"}}}, QueryBrackets, "filter%5Bstatus%5D=open&filter%5Btags%5D=a&filter%5Btags%5D=b", false},
+		{"brackets array of objects", "", map[string]any{"items": []any{map[string]any{"id": 1}}}, QueryBrackets, "items%5B0%5D%5Bid%5D=1", false},
+		{"dots", "", map[string]any{"filter": map[string]any{"status": "open"}}, QueryDots, "filter.status=open", false},
+		{"dots mongo array of objects", "", map[string]any{"items": bson.A{map[string]any{"id": int32(7)}}}, QueryDots, "items.0.id=7", false},
+		{"json", "", map[string]any{"filter": map[string]any{"status": "open"}}, QueryJSON, "filter=%7B%22status%22%3A%22open%22%7D", false},
+		{"nested without encoding", "", map[string]any{"filter": map[string]any{"status": "open"}}, "", "", true},
+		{"null", "", map[string]any{"q": nil}, "", "", true},
+		{"null inside object", "", map[string]any{"filter": map[string]any{"status": nil}}, QueryDots, "", true},
+		{"empty array", "", map[string]any{"id": []any{}}, "", "", true},
+		{"empty mongo array", "", map[string]any{"id": bson.A{}}, QueryJSON, "", true},
+		{"empty array inside object", "", map[string]any{"filter": map[string]any{"ids": []any{}}}, QueryBrackets, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = encodeQuery(q, tt.params, tt.enc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && q.Encode() != tt.want {
+				t.Errorf("encodeQuery() = %s, want %s", q.Encode(), tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateQueryParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		enc     QueryEncoding
+		wantErr bool
+	}{
+		{"string", "x", "", false},
+		{"array", []any{"a"}, "", false},
+		{"empty array", []any{}, "", true},
+		{"empty array with encoding", []any{}, QueryBrackets, true},
+		{"object needs encoding", map[string]any{"a": "b"}, "", true},
+		{"object with encoding", map[string]any{"a": "b"}, QueryDots, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateQueryParam("k", tt.value, tt.enc); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateQueryParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -10,4 +10,6 @@ import (
 
 type Sender interface {
 	SendAlert(ctx context.Context, t models.Task, errMsg string) error
+	// SendNotice sends a message that is not about a single task.
+	SendNotice(ctx context.Context, title, message string) error
 }
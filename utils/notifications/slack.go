@@ -44,6 +44,14 @@ func NewSlackSender(cfg config.Slack, isProd bool) Sender {
 }
 
 func (s *slackSender) SendAlert(ctx context.Context, t models.Task, errMsg string) error {
+	return s.send(ctx, "Exception In Scheduler Service", fmt.Sprintf("```TaskID: %s\nError: %s\n```", t.ID, errMsg))
+}
+
+func (s *slackSender) SendNotice(ctx context.Context, title, message string) error {
+	return s.send(ctx, title, fmt.Sprintf("```%s\n```", message))
+}
+
+func (s *slackSender) send(ctx context.Context, title, text string) error {
 	if !s.isProd && !s.config.SendAlertInDev {
 		return nil
 	}
@@ -52,11 +60,11 @@ func (s *slackSender) SendAlert(ctx context.Context, t models.Task, errMsg strin
 		Blocks: []slackBlock{
 			{
 				Type: "header",
-				Text: slackText{Type: "plain_text", Text: "Exception In Scheduler Service"},
+				Text: slackText{Type: "plain_text", Text: title},
 			},
 			{
 				Type: "section",
-				Text: slackText{Type: "mrkdwn", Text: fmt.Sprintf("```TaskID: %s\nError: %s\n```", t.ID, errMsg)},
+				Text: slackText{Type: "mrkdwn", Text: text},
 			},
 		},
 	}
@@ -0,0 +1,28 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"context"
+	"os"
+	"strings"
+)
+
+type envProvider struct {
+	prefix string
+}
+
+// NewEnvProvider resolves secrets from environment variables. The name
+// "billing-api-token" with prefix "SCHEDULER_SECRET_" is read from
+// SCHEDULER_SECRET_BILLING_API_TOKEN.
+func NewEnvProvider(prefix string) Provider {
+	return &envProvider{prefix: prefix}
+}
+
+func (p *envProvider) Get(_ context.Context, name string) (string, error) {
+	key := p.prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,34 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Local Packages
+	errors "scheduler/errors"
+)
+
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider resolves secrets from files in dir, one file per secret.
+// This matches the layout of a Kubernetes Secret mounted as a volume.
+func NewFileProvider(dir string) Provider {
+	return &fileProvider{dir: dir}
+}
+
+func (p *fileProvider) Get(_ context.Context, name string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(p.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
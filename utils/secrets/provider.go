@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"regexp"
+
+	// Local Packages
+	config "scheduler/config"
+	errors "scheduler/errors"
+)
+
+// ErrNotFound is returned by a Provider when no secret exists with the given name.
+var ErrNotFound = errors.New("secret not found")
+
+// validName restricts secret names to characters that are safe as env var
+// suffixes, file names and Vault path segments. A leading dot is not allowed,
+// which also rules out the "." and ".." path segments and hidden files.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// Provider looks up secret values by name.
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// ResolveError describes a secret reference that could not be resolved.
+type ResolveError struct {
+	Name string
+	Err  error
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("secret %q: %s", e.Name, e.Err)
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// NewProvider builds the provider selected by cfg.Provider.
+func NewProvider(cfg config.Secrets) (Provider, error) {
+	switch cfg.Provider {
+	case "env":
+		return NewEnvProvider(cfg.EnvPrefix), nil
+	case "file":
+		return NewFileProvider(cfg.FileDir), nil
+	case "vault":
+		return NewVaultProvider(cfg.Vault)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider: %s", cfg.Provider)
+	}
+}
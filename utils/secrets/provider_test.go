@@ -0,0 +1,33 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"testing"
+)
+
+func TestValidateRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{"no refs", "Bearer token", false},
+		{"plain name", `Bearer {{secret "billing-api-token"}}`, false},
+		{"dotted name", `{{secret "billing.api_token"}}`, false},
+		{"trailing dot", `{{secret "token."}}`, false},
+		{"empty", `{{secret ""}}`, true},
+		{"current dir", `{{secret "."}}`, true},
+		{"parent dir", `{{secret ".."}}`, true},
+		{"hidden file", `{{secret ".env"}}`, true},
+		{"path", `{{secret "../etc/passwd"}}`, true},
+		{"slash", `{{secret "team/token"}}`, true},
+		{"second ref invalid", `{{secret "ok"}} {{secret ".."}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRefs(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRefs(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// refPattern matches references of the form {{secret "name"}}.
+var refPattern = regexp.MustCompile(`\{\{\s*secret\s+"([^"]*)"\s*\}\}`)
+
+// Resolver expands secret references in task data using a Provider.
+type Resolver struct {
+	provider Provider
+}
+
+func NewResolver(provider Provider) *Resolver {
+	return &Resolver{provider: provider}
+}
+
+// HasRefs reports whether s contains at least one secret reference.
+func HasRefs(s string) bool {
+	return refPattern.MatchString(s)
+}
+
+// RefNames returns the names of the secrets referenced in s.
+func RefNames(s string) []string {
+	var names []string
+	for _, m := range refPattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// ValidateRefs checks that every secret reference in s names a valid secret.
+func ValidateRefs(s string) error {
+	for _, m := range refPattern.FindAllStringSubmatch(s, -1) {
+		if !validName.MatchString(m[1]) {
+			return fmt.Errorf("invalid secret name %q", m[1])
+		}
+	}
+	return nil
+}
+
+// Expand replaces every secret reference in s with its resolved value.
+func (r *Resolver) Expand(ctx context.Context, s string) (string, error) {
+	matches := refPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	out := make([]byte, 0, len(s))
+	last := 0
+	for _, m := range matches {
+		name := s[m[2]:m[3]]
+		if !validName.MatchString(name) {
+			return "", &ResolveError{Name: name, Err: fmt.Errorf("invalid secret name")}
+		}
+		value, err := r.provider.Get(ctx, name)
+		if err != nil {
+			return "", &ResolveError{Name: name, Err: err}
+		}
+		out = append(out, s[last:m[0]]...)
+		out = append(out, value...)
+		last = m[1]
+	}
+	out = append(out, s[last:]...)
+	return string(out), nil
+}
+
+// ExpandStrings returns a copy of m with every value expanded.
+func (r *Resolver) ExpandStrings(ctx context.Context, m map[string]string) (map[string]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		expanded, err := r.Expand(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = expanded
+	}
+	return out, nil
+}
+
+// ExpandValues returns a copy of m with every string, at any depth, expanded.
+func (r *Resolver) ExpandValues(ctx context.Context, m map[string]any) (map[string]any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		expanded, err := r.expandValue(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = expanded
+	}
+	return out, nil
+}
+
+func (r *Resolver) expandValue(ctx context.Context, v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		return r.Expand(ctx, val)
+	case map[string]any:
+		return r.ExpandValues(ctx, val)
+	}
+
+	// Arrays decoded from Mongo are named slice types (bson.A), so match on
+	// kind. Nested documents are plain maps, since the client decodes with
+	// DefaultDocumentMap.
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Interface {
+		return v, nil
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		expanded, err := r.expandValue(ctx, rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		out[i] = expanded
+	}
+	return out, nil
+}
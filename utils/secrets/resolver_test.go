@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"context"
+	"reflect"
+	"testing"
+
+	// External Packages
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// mapProvider serves secrets from a map.
+type mapProvider map[string]string
+
+func (p mapProvider) Get(_ context.Context, name string) (string, error) {
+	if v, ok := p[name]; ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+func TestExpandValues(t *testing.T) {
+	r := NewResolver(mapProvider{"token": "s3cret"})
+	ref := `{{secret "token"}}`
+
+	tests := []struct {
+		name    string
+		in      map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{"string", map[string]any{"auth": "Bearer " + ref}, map[string]any{"auth": "Bearer s3cret"}, false},
+		{"nested object", map[string]any{"a": map[string]any{"b": ref}}, map[string]any{"a": map[string]any{"b": "s3cret"}}, false},
+		{"mongo array", map[string]any{"a": bson.A{ref, int32(1)}}, map[string]any{"a": []any{"s3cret", int32(1)}}, false},
+		{"object in array", map[string]any{"a": []any{map[string]any{"b": ref}}}, map[string]any{"a": []any{map[string]any{"b": "s3cret"}}}, false},
+		{"other values unchanged", map[string]any{"n": 2.5, "ok": true}, map[string]any{"n": 2.5, "ok": true}, false},
+		{"missing secret", map[string]any{"a": `{{secret "nope"}}`}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.ExpandValues(context.Background(), tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
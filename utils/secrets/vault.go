@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	// Go Internal Packages
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	// Local Packages
+	config "scheduler/config"
+)
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+type vaultProvider struct {
+	client *http.Client
+	config config.Vault
+	token  string
+	cache  map[string]cachedSecret
+	mu     sync.Mutex
+}
+
+// NewVaultProvider resolves secrets from a Vault-compatible KV v2 HTTP API.
+// Each secret is read from <mount>/data/<path>/<name> and its value taken from
+// the configured field. Values are cached for cfg.CacheTTL.
+func NewVaultProvider(cfg config.Vault) (Provider, error) {
+	token := cfg.Token
+	if cfg.TokenFile != "" {
+		b, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vault token file: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	return &vaultProvider{
+		client: &http.Client{Timeout: 5 * time.Second},
+		config: cfg,
+		token:  token,
+		cache:  make(map[string]cachedSecret),
+	}, nil
+}
+
+func (p *vaultProvider) Get(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	if c, ok := p.cache[name]; ok && time.Now().Before(c.expiresAt) {
+		p.mu.Unlock()
+		return c.value, nil
+	}
+	p.mu.Unlock()
+
+	value, err := p.fetch(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	if p.config.CacheTTL > 0 {
+		p.mu.Lock()
+		p.cache[name] = cachedSecret{value: value, expiresAt: time.Now().Add(p.config.CacheTTL)}
+		p.mu.Unlock()
+	}
+	return value, nil
+}
+
+func (p *vaultProvider) fetch(ctx context.Context, name string) (string, error) {
+	endpoint, err := url.JoinPath(p.config.Address, "v1", p.config.Mount, "data", p.config.Path, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to build vault url: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call vault: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return "", ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return "", fmt.Errorf("vault returned non-2xx status: %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	value, ok := payload.Data.Data[p.config.Field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret has no string field %q", p.config.Field)
+	}
+	return value, nil
+}
@@ -0,0 +1,136 @@
+package taskio
+
+import (
+	// Go Internal Packages
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	// Local Packages
+	models "scheduler/models"
+
+	// External Packages
+	"gopkg.in/yaml.v3"
+)
+
+// Encode writes tasks as JSON lines or YAML documents. YAML documents use the
+// same field names as the JSON API.
+func Encode(w io.Writer, format models.TransferFormat, tasks []models.Task) error {
+	if format == models.FormatJSONL {
+		enc := json.NewEncoder(w)
+		for i := range tasks {
+			if err := enc.Encode(tasks[i]); err != nil {
+				return fmt.Errorf("failed to encode task %s: %w", tasks[i].ID, err)
+			}
+		}
+		return nil
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for i := range tasks {
+		doc, err := toDocument(tasks[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode task %s: %w", tasks[i].ID, err)
+		}
+		if err = enc.Encode(doc); err != nil {
+			return fmt.Errorf("failed to encode task %s: %w", tasks[i].ID, err)
+		}
+	}
+	return enc.Close()
+}
+
+// Decode reads at most limit tasks written by Encode.
+func Decode(r io.Reader, format models.TransferFormat, limit int) ([]models.Task, error) {
+	var tasks []models.Task
+	add := func(t models.Task) error {
+		if len(tasks) == limit {
+			return fmt.Errorf("too many tasks, at most %d are allowed", limit)
+		}
+		tasks = append(tasks, t)
+		return nil
+	}
+
+	if format == models.FormatJSONL {
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		for {
+			var t models.Task
+			if err := dec.Decode(&t); err == io.EOF {
+				return tasks, nil
+			} else if err != nil {
+				return nil, fmt.Errorf("document %d: %w", len(tasks), err)
+			}
+			if err := add(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dec := yaml.NewDecoder(r)
+	for i := 0; ; i++ {
+		var doc map[string]any
+		if err := dec.Decode(&doc); err == io.EOF {
+			return tasks, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if doc == nil {
+			continue
+		}
+		// Round-trip through JSON so the document shares the API's field names.
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		var t models.Task
+		jd := json.NewDecoder(bytes.NewReader(raw))
+		jd.DisallowUnknownFields()
+		if err = jd.Decode(&t); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if err = add(t); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// toDocument converts a task to a generic document keyed by its JSON field
+// names, keeping integers as integers so YAML does not print them as floats.
+func toDocument(t models.Task) (map[string]any, error) {
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc map[string]any
+	if err = dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(doc).(map[string]any), nil
+}
+
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, value := range v {
+			v[key] = normalizeNumbers(value)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = normalizeNumbers(value)
+		}
+		return v
+	default:
+		return v
+	}
+}